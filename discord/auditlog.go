@@ -0,0 +1,183 @@
+package discord
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// AuditLogAction is the kind of action recorded in an audit log entry
+type AuditLogAction int
+
+// Audit log actions
+const (
+	AuditLogServerUpdate AuditLogAction = 1
+
+	AuditLogChannelCreate          AuditLogAction = 10
+	AuditLogChannelUpdate          AuditLogAction = 11
+	AuditLogChannelDelete          AuditLogAction = 12
+	AuditLogChannelOverwriteCreate AuditLogAction = 13
+	AuditLogChannelOverwriteUpdate AuditLogAction = 14
+	AuditLogChannelOverwriteDelete AuditLogAction = 15
+
+	AuditLogMemberKick       AuditLogAction = 20
+	AuditLogMemberPrune      AuditLogAction = 21
+	AuditLogMemberBanAdd     AuditLogAction = 22
+	AuditLogMemberBanRemove  AuditLogAction = 23
+	AuditLogMemberUpdate     AuditLogAction = 24
+	AuditLogMemberRoleUpdate AuditLogAction = 25
+
+	AuditLogRoleCreate AuditLogAction = 30
+	AuditLogRoleUpdate AuditLogAction = 31
+	AuditLogRoleDelete AuditLogAction = 32
+
+	AuditLogInviteCreate AuditLogAction = 40
+	AuditLogInviteUpdate AuditLogAction = 41
+	AuditLogInviteDelete AuditLogAction = 42
+
+	AuditLogWebhookCreate AuditLogAction = 50
+	AuditLogWebhookUpdate AuditLogAction = 51
+	AuditLogWebhookDelete AuditLogAction = 52
+
+	AuditLogEmojiCreate AuditLogAction = 60
+	AuditLogEmojiUpdate AuditLogAction = 61
+	AuditLogEmojiDelete AuditLogAction = 62
+
+	AuditLogMessageDelete AuditLogAction = 72
+)
+
+var auditLogActionNames = map[AuditLogAction]string{
+	AuditLogServerUpdate:           "GUILD_UPDATE",
+	AuditLogChannelCreate:          "CHANNEL_CREATE",
+	AuditLogChannelUpdate:          "CHANNEL_UPDATE",
+	AuditLogChannelDelete:          "CHANNEL_DELETE",
+	AuditLogChannelOverwriteCreate: "CHANNEL_OVERWRITE_CREATE",
+	AuditLogChannelOverwriteUpdate: "CHANNEL_OVERWRITE_UPDATE",
+	AuditLogChannelOverwriteDelete: "CHANNEL_OVERWRITE_DELETE",
+	AuditLogMemberKick:             "MEMBER_KICK",
+	AuditLogMemberPrune:            "MEMBER_PRUNE",
+	AuditLogMemberBanAdd:           "MEMBER_BAN_ADD",
+	AuditLogMemberBanRemove:        "MEMBER_BAN_REMOVE",
+	AuditLogMemberUpdate:           "MEMBER_UPDATE",
+	AuditLogMemberRoleUpdate:       "MEMBER_ROLE_UPDATE",
+	AuditLogRoleCreate:             "ROLE_CREATE",
+	AuditLogRoleUpdate:             "ROLE_UPDATE",
+	AuditLogRoleDelete:             "ROLE_DELETE",
+	AuditLogInviteCreate:           "INVITE_CREATE",
+	AuditLogInviteUpdate:           "INVITE_UPDATE",
+	AuditLogInviteDelete:           "INVITE_DELETE",
+	AuditLogWebhookCreate:          "WEBHOOK_CREATE",
+	AuditLogWebhookUpdate:          "WEBHOOK_UPDATE",
+	AuditLogWebhookDelete:          "WEBHOOK_DELETE",
+	AuditLogEmojiCreate:            "EMOJI_CREATE",
+	AuditLogEmojiUpdate:            "EMOJI_UPDATE",
+	AuditLogEmojiDelete:            "EMOJI_DELETE",
+	AuditLogMessageDelete:          "MESSAGE_DELETE",
+}
+
+func (action AuditLogAction) String() string {
+	if name, ok := auditLogActionNames[action]; ok {
+		return name
+	}
+	return fmt.Sprintf("AuditLogAction(%d)", int(action))
+}
+
+// AuditLog is a page of the audit log of a server
+type AuditLog struct {
+	Entries []AuditLogEntry `json:"audit_log_entries"`
+	// Users referenced by the entries
+	Users []User `json:"users"`
+}
+
+// AuditLogEntry records who did what on a server
+type AuditLogEntry struct {
+	ID         string           `json:"id"`
+	ActionType AuditLogAction   `json:"action_type"`
+	TargetID   string           `json:"target_id"`
+	UserID     string           `json:"user_id"`
+	Changes    []AuditLogChange `json:"changes"`
+	Options    *AuditLogOptions `json:"options"`
+	Reason     string           `json:"reason"`
+}
+
+// AuditLogChange is the change of one property, Key being its JSON name
+// such as "name" or "permissions"
+type AuditLogChange struct {
+	Key      string      `json:"key"`
+	OldValue interface{} `json:"old_value"`
+	NewValue interface{} `json:"new_value"`
+}
+
+// AuditLogOptions holds additional details of some actions
+type AuditLogOptions struct {
+	DeleteMemberDays string `json:"delete_member_days"`
+	MembersRemoved   string `json:"members_removed"`
+	ChannelID        string `json:"channel_id"`
+	Count            string `json:"count"`
+	ID               string `json:"id"`
+	Type             string `json:"type"`
+	RoleName         string `json:"role_name"`
+}
+
+// AuditLogParams filters the audit log entries, empty fields are ignored
+type AuditLogParams struct {
+	UserID     string
+	ActionType AuditLogAction
+	// Only entries older than this entry ID, to get the next page
+	Before string
+	// Up to 100
+	Limit int
+}
+
+// GetUser returns the user who did the action, from the users of the log
+func (log *AuditLog) GetUser(entry AuditLogEntry) User {
+	var res User
+	for _, user := range log.Users {
+		if user.ID == entry.UserID {
+			res = user
+			break
+		}
+	}
+	return res
+}
+
+// AuditLog returns the latest entries of the audit log of the server,
+// newest first
+func (c *Client) AuditLog(serverID string, params AuditLogParams) (AuditLog, error) {
+	var auditLog AuditLog
+
+	if err := c.checkServerPermissions(serverID, PermissionViewAuditLog); err != nil {
+		return auditLog, err
+	}
+
+	v := url.Values{}
+	if params.UserID != "" {
+		v.Set("user_id", params.UserID)
+	}
+	if params.ActionType != 0 {
+		v.Set("action_type", strconv.Itoa(int(params.ActionType)))
+	}
+	if params.Before != "" {
+		v.Set("before", params.Before)
+	}
+	if params.Limit > 0 {
+		v.Set("limit", strconv.Itoa(params.Limit))
+	}
+
+	u := fmt.Sprintf("%s/%s/audit-logs", apiServers, serverID)
+	if len(v) > 0 {
+		u += "?" + v.Encode()
+	}
+
+	response, err := c.get(u)
+	if err != nil {
+		return auditLog, err
+	}
+
+	if err := json.Unmarshal(response, &auditLog); err != nil {
+		return auditLog, err
+	}
+
+	return auditLog, nil
+}
@@ -1,26 +1,196 @@
 package discord
 
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Messageable is anything messages can be sent to and read from: server
+// channels, private channels (group DMs included), the channel of a Message,
+// or a bare ChannelID
+type Messageable interface {
+	GetChannelID() string
+}
+
+// ChannelID makes a raw channel ID usable as a Messageable
+type ChannelID string
+
+// GetChannelID returns the ID itself
+func (id ChannelID) GetChannelID() string {
+	return string(id)
+}
+
+// ChannelType is the kind of a channel. Types unknown to this package are
+// kept as their number, such as "5".
+type ChannelType string
+
+// Channel types
+const (
+	ChannelText     ChannelType = "text"
+	ChannelPrivate  ChannelType = "private"
+	ChannelVoice    ChannelType = "voice"
+	ChannelGroup    ChannelType = "group"
+	ChannelCategory ChannelType = "category"
+)
+
+// Numeric types of newer versions of the API, categories only exist there
+var channelTypeNumbers = map[int]ChannelType{
+	0: ChannelText,
+	1: ChannelPrivate,
+	2: ChannelVoice,
+	3: ChannelGroup,
+	4: ChannelCategory,
+}
+
+// MarshalJSON sends the type as a number, as names are not accepted for
+// categories
+func (t ChannelType) MarshalJSON() ([]byte, error) {
+	for number, channelType := range channelTypeNumbers {
+		if channelType == t {
+			return json.Marshal(number)
+		}
+	}
+	if number, err := strconv.Atoi(string(t)); err == nil {
+		return json.Marshal(number)
+	}
+	return json.Marshal(string(t))
+}
+
+// UnmarshalJSON reads the type either as a name or as a number
+func (t *ChannelType) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*t = ChannelType(name)
+		return nil
+	}
+
+	var number int
+	if err := json.Unmarshal(data, &number); err != nil {
+		return err
+	}
+	channelType, ok := channelTypeNumbers[number]
+	if !ok {
+		// Newer types must not prevent decoding the rest of the payload
+		channelType = ChannelType(strconv.Itoa(number))
+	}
+	*t = channelType
+	return nil
+}
+
 // Channel defines everything about a channel
 type Channel struct {
-	Name          string `json:"name"`
-	ID            string `json:"id"`
-	Topic         string `json:"topic"`
-	LastMessageID string `json:"last_message_id"`
-	Type          string `json:"type"`
-	Position      int    `json:"position"`
-	ServerID      string `json:"guild_id"`
+	Name          string      `json:"name"`
+	ID            string      `json:"id"`
+	Topic         string      `json:"topic"`
+	LastMessageID string      `json:"last_message_id"`
+	Type          ChannelType `json:"type"`
+	Position      int         `json:"position"`
+	ServerID      string      `json:"guild_id"`
+	// Category the channel belongs to
+	ParentID string `json:"parent_id"`
+	// Voice channels only
+	Bitrate   int `json:"bitrate"`
+	UserLimit int `json:"user_limit"`
+
+	PermissionOverwrites []PermissionOverwrite `json:"permission_overwrites"`
+}
+
+// ChannelParams describes a channel to create
+type ChannelParams struct {
+	Name     string      `json:"name"`
+	Type     ChannelType `json:"type"`
+	Topic    string      `json:"topic,omitempty"`
+	ParentID string      `json:"parent_id,omitempty"`
+	// Voice channels only, bitrate in bits per second and 0 users for no limit
+	Bitrate   int `json:"bitrate,omitempty"`
+	UserLimit int `json:"user_limit,omitempty"`
+
+	PermissionOverwrites []PermissionOverwrite `json:"permission_overwrites,omitempty"`
+}
+
+// Validate checks the parameters against what Discord accepts
+func (params *ChannelParams) Validate() error {
+	if n := len([]rune(params.Name)); n < 2 || n > 100 {
+		return fmt.Errorf("channel name must be 2 to 100 characters long, got %d", n)
+	}
 
-	// Don't know how its formatted yet
-	PermissionOverwrites []interface{} `json:"permission_overwrites"`
+	switch params.Type {
+	case ChannelText:
+		if params.Bitrate != 0 || params.UserLimit != 0 {
+			return fmt.Errorf("bitrate and user limit only apply to voice channels")
+		}
+		if n := len([]rune(params.Topic)); n > 1024 {
+			return fmt.Errorf("channel topic is %d characters long, limit is 1024", n)
+		}
+	case ChannelVoice:
+		if params.Topic != "" {
+			return fmt.Errorf("topic only applies to text channels")
+		}
+		if params.Bitrate != 0 && (params.Bitrate < 8000 || params.Bitrate > 96000) {
+			return fmt.Errorf("bitrate must be between 8000 and 96000, got %d", params.Bitrate)
+		}
+		if params.UserLimit < 0 || params.UserLimit > 99 {
+			return fmt.Errorf("user limit must be between 0 and 99, got %d", params.UserLimit)
+		}
+	case ChannelCategory:
+		if params.Topic != "" || params.Bitrate != 0 || params.UserLimit != 0 || params.ParentID != "" {
+			return fmt.Errorf("categories only have a name and permission overwrites")
+		}
+	default:
+		return fmt.Errorf("cannot create a channel of type %q", params.Type)
+	}
+	return nil
+}
+
+// GetChannelID returns the ID of the channel, it implements Messageable
+func (channel Channel) GetChannelID() string {
+	return channel.ID
 }
 
 func (channel *Channel) GetServer(client *Client) Server {
-	return client.Servers[channel.ServerID]
+	return client.GetServerByID(channel.ServerID)
 }
 
 // SendMessage sends a message to the channel
 func (channel *Channel) SendMessage(client *Client, content string) (Message, error) {
-	return client.SendMessage(channel.ID, content)
+	return client.SendMessage(*channel, content)
+}
+
+// SendMessageComplex sends a message with an embed or files to the channel
+func (channel *Channel) SendMessageComplex(client *Client, params MessageParams) (Message, error) {
+	return client.SendMessageComplex(*channel, params)
+}
+
+// PinnedMessages returns the pinned messages of the channel
+func (channel *Channel) PinnedMessages(client *Client) ([]Message, error) {
+	return client.PinnedMessages(*channel)
+}
+
+// TriggerTyping shows the client as typing in the channel
+func (channel *Channel) TriggerTyping(client *Client) error {
+	return client.TriggerTyping(*channel)
+}
+
+// everyoneOverwrite returns the overwrite of the @everyone role, which
+// shares the ID of the server
+func (channel *Channel) everyoneOverwrite() PermissionOverwrite {
+	for _, overwrite := range channel.PermissionOverwrites {
+		if overwrite.ID == channel.ServerID {
+			return overwrite
+		}
+	}
+	return PermissionOverwrite{ID: channel.ServerID, Type: OverwriteRole}
+}
+
+// CreateInvite creates an invite to the channel, see Client.CreateInvite
+func (channel *Channel) CreateInvite(client *Client, maxAge int, maxUses int, temporary bool, unique bool, opts ...RequestOption) (Invite, error) {
+	return client.CreateInvite(channel.ID, maxAge, maxUses, temporary, unique, opts...)
+}
+
+// Delete deletes the channel
+func (channel *Channel) Delete(client *Client, opts ...RequestOption) (Channel, error) {
+	return client.DeleteChannel(channel.ID, opts...)
 }
 
 type channelEvent struct {
@@ -29,16 +199,46 @@ type channelEvent struct {
 	Data   Channel `json:"d"`
 }
 
-// PrivateChannel defines everything about a private one-to-one conversation
+// ChannelPinsUpdate is received when a message is pinned or unpinned
+type ChannelPinsUpdate struct {
+	ChannelID        string `json:"channel_id"`
+	LastPinTimestamp string `json:"last_pin_timestamp"`
+}
+
+type channelPinsUpdateEvent struct {
+	OpCode int               `json:"op"`
+	Type   string            `json:"t"`
+	Data   ChannelPinsUpdate `json:"d"`
+}
+
+// PrivateChannel defines everything about a private conversation, either
+// one-to-one or a group DM
 type PrivateChannel struct {
 	ID            string `json:"id"`
 	Recipient     User   `json:"recipient"`
 	LastMessageID string `json:"last_message_id"`
+	// Group DMs only
+	Name       string `json:"name"`
+	Icon       string `json:"icon"`
+	OwnerID    string `json:"owner_id"`
+	Recipients []User `json:"recipients"`
+}
+
+// GetChannelID returns the ID of the private channel, it implements
+// Messageable
+func (private PrivateChannel) GetChannelID() string {
+	return private.ID
 }
 
-// SendMessage sends a message to the user linked to the PrivateChannel
+// SendMessage sends a message to the users linked to the PrivateChannel
 func (private *PrivateChannel) SendMessage(client *Client, content string) (Message, error) {
-	return client.SendMessage(private.ID, content)
+	return client.SendMessage(*private, content)
+}
+
+// SendMessageComplex sends a message with an embed or files to the users
+// linked to the PrivateChannel
+func (private *PrivateChannel) SendMessageComplex(client *Client, params MessageParams) (Message, error) {
+	return client.SendMessageComplex(*private, params)
 }
 
 type privateChannelEvent struct {
@@ -0,0 +1,63 @@
+package discord
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestChannelTypeJSON(t *testing.T) {
+	tests := []struct {
+		channelType ChannelType
+		json        string
+	}{
+		{ChannelText, "0"},
+		{ChannelPrivate, "1"},
+		{ChannelVoice, "2"},
+		{ChannelGroup, "3"},
+		{ChannelCategory, "4"},
+		// Unknown types are kept as their number
+		{ChannelType("5"), "5"},
+	}
+
+	for _, test := range tests {
+		data, err := json.Marshal(ChannelParams{Name: "name", Type: test.channelType})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var sent struct {
+			Type json.RawMessage `json:"type"`
+		}
+		if err := json.Unmarshal(data, &sent); err != nil {
+			t.Fatal(err)
+		}
+		if string(sent.Type) != test.json {
+			t.Errorf("%s: sent %s, want %s", test.channelType, sent.Type, test.json)
+		}
+
+		// Both the name and the number are read back
+		for _, encoded := range []string{test.json, `"` + string(test.channelType) + `"`} {
+			var decoded ChannelType
+			if err := json.Unmarshal([]byte(encoded), &decoded); err != nil {
+				t.Errorf("%s: %v", encoded, err)
+			} else if decoded != test.channelType {
+				t.Errorf("%s: decoded %s, want %s", encoded, decoded, test.channelType)
+			}
+		}
+	}
+}
+
+func TestUnknownChannelType(t *testing.T) {
+	var server Server
+	data := `{"id":"1","channels":[{"id":"2","type":0},{"id":"3","type":13}]}`
+	if err := json.Unmarshal([]byte(data), &server); err != nil {
+		t.Fatalf("server with an unknown channel type: %v", err)
+	}
+	if len(server.Channels) != 2 || server.Channels[1].Type != ChannelType("13") {
+		t.Errorf("channels = %+v", server.Channels)
+	}
+
+	params := ChannelParams{Name: "name", Type: ChannelType("13")}
+	if err := params.Validate(); err == nil {
+		t.Error("Validate accepted an unknown channel type")
+	}
+}
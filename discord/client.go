@@ -2,11 +2,20 @@ package discord
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -24,43 +33,85 @@ const (
 	apiInvite   = apiBase + "/invite"
 	apiUsers    = apiBase + "/users"
 	apiVoice    = apiBase + "/voice"
+	apiWebhooks = apiBase + "/webhooks"
 )
 
 // Client is the main object, instantiate it to use Discord Websocket API
 type Client struct {
-	OnReady                func(Ready)
-	OnMessageCreate        func(Message)
-	OnMessageAck           func(Message) // Only contains `id` and `channel_id`
-	OnMessageUpdate        func(Message)
-	OnMessageDelete        func(Message) // Only contains `id` and `channel_id`
-	OnTypingStart          func(Typing)
-	OnPresenceUpdate       func(Presence)
-	OnChannelCreate        func(Channel)
-	OnChannelUpdate        func(Channel)
-	OnChannelDelete        func(Channel)
-	OnPrivateChannelCreate func(PrivateChannel)
-	OnPrivateChannelDelete func(PrivateChannel)
-	OnServerCreate         func(Server)
-	OnServerDelete         func(Server)
-	OnServerMemberAdd      func(Member)
-	OnServerMemberDelete   func(Member)
+	OnReady                    func(Ready)
+	OnMessageCreate            func(Message)
+	OnMessageAck               func(Message) // Only contains `id` and `channel_id`
+	OnMessageUpdate            func(Message)
+	OnMessageDelete            func(Message) // Only contains `id` and `channel_id`
+	OnMessageDeleteBulk        func(MessageDeleteBulk)
+	OnTypingStart              func(Typing)
+	OnMessageReactionAdd       func(MessageReaction)
+	OnMessageReactionRemove    func(MessageReaction)
+	OnMessageReactionRemoveAll func(MessageReaction) // Only contains `channel_id` and `message_id`
+	OnPresenceUpdate           func(Presence)
+	OnChannelCreate            func(Channel)
+	OnChannelUpdate            func(Channel)
+	OnChannelDelete            func(Channel)
+	OnChannelPinsUpdate        func(ChannelPinsUpdate)
+	OnPrivateChannelCreate     func(PrivateChannel)
+	OnPrivateChannelDelete     func(PrivateChannel)
+	OnServerCreate             func(Server)
+	OnServerDelete             func(Server)
+	OnServerEmojisUpdate       func(ServerEmojisUpdate)
+	OnServerBanAdd             func(Ban) // No reason given
+	OnServerBanRemove          func(Ban)
+	OnServerMemberAdd          func(Member)
+	OnServerMemberDelete       func(Member)
 
 	// Reconnect upon websocket close server-side (EOF)
 	Reconnect bool
 
 	// Print websocket dumps (may be huge)
 	Debug bool
+
+	// Compute the permissions of the client from the cache before sending
+	// requests which need them, and fail with a MissingPermissionsError
+	CheckPermissions bool
+
+	// Number of messages kept per channel in Messages, 0 disables the cache
+	MaxMessages int
+
+	// File in which the token is kept between runs, leave empty to always
+	// login with credentials
+	TokenFile string
+
 	// Accessible, but you shouldn't modify these (I may put some getters there)
 	User            User
 	Servers         map[string]Server
 	PrivateChannels map[string]PrivateChannel
-
+	// Last messages of each channel by channel ID, oldest first. Events are
+	// handled concurrently, use GetMessageByID instead of reading it.
+	Messages map[string][]Message
+
+	// Events are handled concurrently, serversLock guards Servers and
+	// PrivateChannels and messagesLock guards Messages
+	serversLock     sync.RWMutex
+	messagesLock    sync.RWMutex
 	wsConn          *websocket.Conn
 	gateway         gatewayStruct
 	token           tokenStruct
 	keepaliveTicker *time.Ticker
 }
 
+// APIError is returned when Discord answers a request with an error status
+type APIError struct {
+	StatusCode int
+	Code       int    `json:"code"`
+	Message    string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("discord: HTTP %d", e.StatusCode)
+	}
+	return fmt.Sprintf("discord: HTTP %d: %s", e.StatusCode, e.Message)
+}
+
 func (c *Client) doRequest(req *http.Request) ([]byte, error) {
 	client := &http.Client{}
 
@@ -68,24 +119,89 @@ func (c *Client) doRequest(req *http.Request) ([]byte, error) {
 		"User-Agent",
 		fmt.Sprintf("DiscordBot (https://github.com/gdraynz/go-discord, %s)", VERSION),
 	)
+	// Webhooks can be executed without being logged in
+	if c.token.Value != "" {
+		req.Header.Set("Authorization", c.token.Value)
+	}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
+	for {
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		// JSON from payload
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		if c.Debug {
+			log.Printf("%s %s : %s", req.Method, redactURL(req.URL), string(body[:]))
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests {
+			if err := waitRateLimit(req, body); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if resp.StatusCode >= 400 {
+			apiErr := &APIError{StatusCode: resp.StatusCode}
+			// Body is not always JSON, the status is enough in that case
+			json.Unmarshal(body, apiErr)
+			return body, apiErr
+		}
+
+		return body, nil
 	}
-	defer resp.Body.Close()
+}
 
-	// JSON from payload
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+// redactURL returns the URL for logging, without the token of webhooks
+// (/webhooks/{id}/{token})
+func redactURL(u *url.URL) string {
+	parts := strings.Split(u.Path, "/")
+	for i := range parts {
+		if parts[i] == "webhooks" && i+2 < len(parts) {
+			parts[i+2] = "REDACTED"
+			break
+		}
 	}
+	redacted := *u
+	redacted.Path = strings.Join(parts, "/")
+	redacted.RawPath = ""
+	return redacted.String()
+}
 
-	if c.Debug {
-		log.Printf("%s %s : %s", req.Method, req.URL.String, string(body[:]))
+// waitRateLimit sleeps for as long as Discord asks and rewinds the request
+// body so that it can be sent again
+func waitRateLimit(req *http.Request, body []byte) error {
+	var rateLimit struct {
+		RetryAfter int `json:"retry_after"` // milliseconds
+	}
+	json.Unmarshal(body, &rateLimit)
+	wait := time.Duration(rateLimit.RetryAfter) * time.Millisecond
+	if wait <= 0 {
+		wait = time.Second
+	}
+	log.Printf("Rate limited on %s %s, retrying in %s", req.Method, redactURL(req.URL), wait)
+
+	select {
+	case <-time.After(wait):
+	case <-req.Context().Done():
+		return req.Context().Err()
 	}
 
-	return body, err
+	if req.GetBody != nil {
+		reqBody, err := req.GetBody()
+		if err != nil {
+			return err
+		}
+		req.Body = reqBody
+	}
+	return nil
 }
 
 func (c *Client) doHandshake() {
@@ -107,6 +223,12 @@ func (c *Client) doHandshake() {
 }
 
 func (c *Client) initServers(ready Ready) {
+	c.messagesLock.Lock()
+	c.Messages = make(map[string][]Message)
+	c.messagesLock.Unlock()
+
+	c.serversLock.Lock()
+	defer c.serversLock.Unlock()
 	c.Servers = make(map[string]Server)
 	c.PrivateChannels = make(map[string]PrivateChannel)
 	for _, server := range ready.Servers {
@@ -158,6 +280,14 @@ func (c *Client) handleReady(eventStr []byte) {
 }
 
 func (c *Client) handleMessageCreate(eventStr []byte) {
+	var message messageEvent
+	if err := json.Unmarshal(eventStr, &message); err != nil {
+		log.Printf("messageCreate: %s", err)
+		return
+	}
+
+	c.cacheMessage(message.Data)
+
 	if c.OnMessageCreate == nil {
 		if c.Debug {
 			log.Print("No handler for MESSAGE_CREATE")
@@ -165,12 +295,6 @@ func (c *Client) handleMessageCreate(eventStr []byte) {
 		return
 	}
 
-	var message messageEvent
-	if err := json.Unmarshal(eventStr, &message); err != nil {
-		log.Printf("messageCreate: %s", err)
-		return
-	}
-
 	if message.Data.Author.ID != c.User.ID {
 		c.OnMessageCreate(message.Data)
 	} else {
@@ -196,6 +320,14 @@ func (c *Client) handleMessageAck(eventStr []byte) {
 }
 
 func (c *Client) handleMessageUpdate(eventStr []byte) {
+	var message messageEvent
+	if err := json.Unmarshal(eventStr, &message); err != nil {
+		log.Printf("messageUpdate: %s", err)
+		return
+	}
+
+	c.updateCachedMessage(message.Data)
+
 	if c.OnMessageUpdate == nil {
 		if c.Debug {
 			log.Print("No handler for MESSAGE_UPDATE")
@@ -203,12 +335,6 @@ func (c *Client) handleMessageUpdate(eventStr []byte) {
 		return
 	}
 
-	var message messageEvent
-	if err := json.Unmarshal(eventStr, &message); err != nil {
-		log.Printf("messageUpdate: %s", err)
-		return
-	}
-
 	if message.Data.Author.ID != c.User.ID {
 		c.OnMessageUpdate(message.Data)
 	} else {
@@ -217,6 +343,14 @@ func (c *Client) handleMessageUpdate(eventStr []byte) {
 }
 
 func (c *Client) handleMessageDelete(eventStr []byte) {
+	var message messageEvent
+	if err := json.Unmarshal(eventStr, &message); err != nil {
+		log.Printf("messageDelete: %s", err)
+		return
+	}
+
+	c.uncacheMessage(message.Data.ChannelID, message.Data.ID)
+
 	if c.OnMessageDelete == nil {
 		if c.Debug {
 			log.Print("No handler for MESSAGE_DELETE")
@@ -224,13 +358,127 @@ func (c *Client) handleMessageDelete(eventStr []byte) {
 		return
 	}
 
-	var message messageEvent
-	if err := json.Unmarshal(eventStr, &message); err != nil {
-		log.Printf("messageDelete: %s", err)
+	c.OnMessageDelete(message.Data)
+}
+
+func (c *Client) handleMessageReactionAdd(eventStr []byte) {
+	var event messageReactionEvent
+	if err := json.Unmarshal(eventStr, &event); err != nil {
+		log.Printf("messageReactionAdd: %s", err)
 		return
 	}
 
-	c.OnMessageDelete(message.Data)
+	reaction := event.Data
+	c.messagesLock.Lock()
+	if i := c.getMessageIndex(reaction.ChannelID, reaction.MessageID); i >= 0 {
+		message := &c.Messages[reaction.ChannelID][i]
+		found := false
+		for j := range message.Reactions {
+			if message.Reactions[j].Emoji.Equals(reaction.Emoji) {
+				message.Reactions[j].Count++
+				if reaction.UserID == c.User.ID {
+					message.Reactions[j].Me = true
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			message.Reactions = append(message.Reactions, Reaction{
+				Count: 1,
+				Me:    reaction.UserID == c.User.ID,
+				Emoji: reaction.Emoji,
+			})
+		}
+	}
+	c.messagesLock.Unlock()
+
+	if c.OnMessageReactionAdd == nil {
+		if c.Debug {
+			log.Print("No handler for MESSAGE_REACTION_ADD")
+		}
+	} else {
+		c.OnMessageReactionAdd(reaction)
+	}
+}
+
+func (c *Client) handleMessageReactionRemove(eventStr []byte) {
+	var event messageReactionEvent
+	if err := json.Unmarshal(eventStr, &event); err != nil {
+		log.Printf("messageReactionRemove: %s", err)
+		return
+	}
+
+	reaction := event.Data
+	c.messagesLock.Lock()
+	if i := c.getMessageIndex(reaction.ChannelID, reaction.MessageID); i >= 0 {
+		message := &c.Messages[reaction.ChannelID][i]
+		for j := range message.Reactions {
+			if message.Reactions[j].Emoji.Equals(reaction.Emoji) {
+				message.Reactions[j].Count--
+				if reaction.UserID == c.User.ID {
+					message.Reactions[j].Me = false
+				}
+				if message.Reactions[j].Count <= 0 {
+					message.Reactions = append(message.Reactions[:j], message.Reactions[j+1:]...)
+				}
+				break
+			}
+		}
+	}
+	c.messagesLock.Unlock()
+
+	if c.OnMessageReactionRemove == nil {
+		if c.Debug {
+			log.Print("No handler for MESSAGE_REACTION_REMOVE")
+		}
+	} else {
+		c.OnMessageReactionRemove(reaction)
+	}
+}
+
+func (c *Client) handleMessageReactionRemoveAll(eventStr []byte) {
+	var event messageReactionEvent
+	if err := json.Unmarshal(eventStr, &event); err != nil {
+		log.Printf("messageReactionRemoveAll: %s", err)
+		return
+	}
+
+	reaction := event.Data
+	c.messagesLock.Lock()
+	if i := c.getMessageIndex(reaction.ChannelID, reaction.MessageID); i >= 0 {
+		c.Messages[reaction.ChannelID][i].Reactions = nil
+	}
+	c.messagesLock.Unlock()
+
+	if c.OnMessageReactionRemoveAll == nil {
+		if c.Debug {
+			log.Print("No handler for MESSAGE_REACTION_REMOVE_ALL")
+		}
+	} else {
+		c.OnMessageReactionRemoveAll(reaction)
+	}
+}
+
+func (c *Client) handleMessageDeleteBulk(eventStr []byte) {
+	var event messageDeleteBulkEvent
+	if err := json.Unmarshal(eventStr, &event); err != nil {
+		log.Printf("messageDeleteBulk: %s", err)
+		return
+	}
+
+	for _, id := range event.Data.IDs {
+		c.uncacheMessage(event.Data.ChannelID, id)
+	}
+
+	if c.OnMessageDeleteBulk == nil {
+		if c.Debug {
+			log.Print("No handler for MESSAGE_DELETE_BULK")
+		}
+		return
+	}
+
+	c.OnMessageDeleteBulk(event.Data)
 }
 
 func (c *Client) handleTypingStart(eventStr []byte) {
@@ -285,7 +533,9 @@ func (c *Client) handleChannelCreate(eventStr []byte) {
 		}
 
 		privateChannel := event.Data
+		c.serversLock.Lock()
 		c.PrivateChannels[privateChannel.ID] = privateChannel
+		c.serversLock.Unlock()
 
 		if c.OnPrivateChannelCreate == nil {
 			if c.Debug {
@@ -304,9 +554,16 @@ func (c *Client) handleChannelCreate(eventStr []byte) {
 		channel := event.Data
 		// XXX: Workaround for c.Channels[private.ID].Private = true
 		// https://github.com/golang/go/issues/3117
+		c.serversLock.Lock()
 		tmp := c.Servers[channel.ServerID]
-		tmp.Channels = append(tmp.Channels, channel)
+		// CreateChannel may have cached it already
+		if i := c.getChannelIndex(channel.ServerID, channel.ID); i >= 0 {
+			tmp.Channels[i] = channel
+		} else {
+			tmp.Channels = append(tmp.Channels, channel)
+		}
 		c.Servers[channel.ServerID] = tmp
+		c.serversLock.Unlock()
 
 		if c.OnChannelCreate == nil {
 			if c.Debug {
@@ -326,14 +583,24 @@ func (c *Client) handleChannelUpdate(eventStr []byte) {
 	}
 
 	channel := event.Data
-	// Get channel id in slice of server
-	i := c.getChannelIndex(channel.ID)
 	// XXX: Workaround for c.Servers[channel.ServerID].Channels = ...
 	// https://github.com/golang/go/issues/3117
+	c.serversLock.Lock()
 	tmp := c.Servers[channel.ServerID]
-	tmp.Channels = append(tmp.Channels[:i], tmp.Channels[i+1:]...)
-	tmp.Channels = append(tmp.Channels, channel)
+	found := false
+	for i := range tmp.Channels {
+		// Replaced as a whole, permission overwrites included
+		if tmp.Channels[i].ID == channel.ID {
+			tmp.Channels[i] = channel
+			found = true
+			break
+		}
+	}
+	if !found {
+		tmp.Channels = append(tmp.Channels, channel)
+	}
 	c.Servers[channel.ServerID] = tmp
+	c.serversLock.Unlock()
 
 	if c.OnChannelUpdate == nil {
 		if c.Debug {
@@ -362,7 +629,9 @@ func (c *Client) handleChannelDelete(eventStr []byte) {
 		}
 
 		privateChannel := event.Data
+		c.serversLock.Lock()
 		delete(c.PrivateChannels, privateChannel.ID)
+		c.serversLock.Unlock()
 
 		if c.OnPrivateChannelDelete == nil {
 			if c.Debug {
@@ -379,13 +648,7 @@ func (c *Client) handleChannelDelete(eventStr []byte) {
 		}
 
 		channel := event.Data
-		// Get channel id in slice of server
-		i := c.getChannelIndex(channel.ID)
-		// XXX: Workaround for c.Servers[channel.ServerID].Channels = ...
-		// https://github.com/golang/go/issues/3117
-		tmp := c.Servers[channel.ServerID]
-		tmp.Channels = append(tmp.Channels[:i], tmp.Channels[i+1:]...)
-		c.Servers[channel.ServerID] = tmp
+		c.uncacheChannel(channel.ServerID, channel.ID)
 
 		if c.OnChannelDelete == nil {
 			if c.Debug {
@@ -397,6 +660,23 @@ func (c *Client) handleChannelDelete(eventStr []byte) {
 	}
 }
 
+func (c *Client) handleChannelPinsUpdate(eventStr []byte) {
+	if c.OnChannelPinsUpdate == nil {
+		if c.Debug {
+			log.Print("No handler for CHANNEL_PINS_UPDATE")
+		}
+		return
+	}
+
+	var event channelPinsUpdateEvent
+	if err := json.Unmarshal(eventStr, &event); err != nil {
+		log.Printf("channelPinsUpdate: %s", err)
+		return
+	}
+
+	c.OnChannelPinsUpdate(event.Data)
+}
+
 func (c *Client) handleGuildCreate(eventStr []byte) {
 	var event serverEvent
 	if err := json.Unmarshal(eventStr, &event); err != nil {
@@ -405,7 +685,9 @@ func (c *Client) handleGuildCreate(eventStr []byte) {
 	}
 
 	server := event.Data
+	c.serversLock.Lock()
 	c.Servers[server.ID] = server
+	c.serversLock.Unlock()
 
 	if c.OnServerCreate == nil {
 		if c.Debug {
@@ -424,7 +706,9 @@ func (c *Client) handleGuildDelete(eventStr []byte) {
 	}
 
 	server := event.Data
+	c.serversLock.Lock()
 	delete(c.Servers, server.ID)
+	c.serversLock.Unlock()
 
 	if c.OnServerDelete == nil {
 		if c.Debug {
@@ -435,6 +719,65 @@ func (c *Client) handleGuildDelete(eventStr []byte) {
 	}
 }
 
+func (c *Client) handleGuildEmojisUpdate(eventStr []byte) {
+	var event serverEmojisUpdateEvent
+	if err := json.Unmarshal(eventStr, &event); err != nil {
+		log.Printf("guildEmojisUpdate: %s", err)
+		return
+	}
+
+	update := event.Data
+	// https://github.com/golang/go/issues/3117
+	c.serversLock.Lock()
+	if tmp, ok := c.Servers[update.ServerID]; ok {
+		tmp.Emojis = update.Emojis
+		c.Servers[update.ServerID] = tmp
+	}
+	c.serversLock.Unlock()
+
+	if c.OnServerEmojisUpdate == nil {
+		if c.Debug {
+			log.Print("No handler for GUILD_EMOJIS_UPDATE")
+		}
+	} else {
+		c.OnServerEmojisUpdate(update)
+	}
+}
+
+func (c *Client) handleGuildBanAdd(eventStr []byte) {
+	if c.OnServerBanAdd == nil {
+		if c.Debug {
+			log.Print("No handler for GUILD_BAN_ADD")
+		}
+		return
+	}
+
+	var event banEvent
+	if err := json.Unmarshal(eventStr, &event); err != nil {
+		log.Printf("guildBanAdd: %s", err)
+		return
+	}
+
+	c.OnServerBanAdd(event.Data)
+}
+
+func (c *Client) handleGuildBanRemove(eventStr []byte) {
+	if c.OnServerBanRemove == nil {
+		if c.Debug {
+			log.Print("No handler for GUILD_BAN_REMOVE")
+		}
+		return
+	}
+
+	var event banEvent
+	if err := json.Unmarshal(eventStr, &event); err != nil {
+		log.Printf("guildBanRemove: %s", err)
+		return
+	}
+
+	c.OnServerBanRemove(event.Data)
+}
+
 func (c *Client) handleGuildMemberAdd(eventStr []byte) {
 	var event memberEvent
 	if err := json.Unmarshal(eventStr, &event); err != nil {
@@ -444,9 +787,11 @@ func (c *Client) handleGuildMemberAdd(eventStr []byte) {
 
 	member := event.Data
 	// https://github.com/golang/go/issues/3117
+	c.serversLock.Lock()
 	tmp := c.Servers[member.ServerID]
 	tmp.Members = append(tmp.Members, member)
 	c.Servers[member.ServerID] = tmp
+	c.serversLock.Unlock()
 
 	if c.OnServerMemberAdd == nil {
 		if c.Debug {
@@ -466,15 +811,18 @@ func (c *Client) handleGuildMemberDelete(eventStr []byte) {
 
 	member := event.Data
 	// Get member id in slice of server
-	i := c.getMemberIndex(member.User.ID)
-	// https://github.com/golang/go/issues/3117
-	tmp := c.Servers[member.ServerID]
-	tmp.Members = append(tmp.Members[:i], tmp.Members[i+1:]...)
-	c.Servers[member.ServerID] = tmp
+	c.serversLock.Lock()
+	if i := c.getMemberIndex(member.ServerID, member.User.ID); i >= 0 {
+		// https://github.com/golang/go/issues/3117
+		tmp := c.Servers[member.ServerID]
+		tmp.Members = append(tmp.Members[:i], tmp.Members[i+1:]...)
+		c.Servers[member.ServerID] = tmp
+	}
+	c.serversLock.Unlock()
 
 	if c.OnServerMemberDelete == nil {
 		if c.Debug {
-			log.Print("No handler for GUILD_MEMBER_DELETE")
+			log.Print("No handler for GUILD_MEMBER_REMOVE")
 		}
 	} else {
 		c.OnServerMemberDelete(member)
@@ -507,6 +855,14 @@ func (c *Client) handleEvent(eventStr []byte) {
 		c.handleMessageUpdate(eventStr)
 	case "MESSAGE_DELETE":
 		c.handleMessageDelete(eventStr)
+	case "MESSAGE_DELETE_BULK":
+		c.handleMessageDeleteBulk(eventStr)
+	case "MESSAGE_REACTION_ADD":
+		c.handleMessageReactionAdd(eventStr)
+	case "MESSAGE_REACTION_REMOVE":
+		c.handleMessageReactionRemove(eventStr)
+	case "MESSAGE_REACTION_REMOVE_ALL":
+		c.handleMessageReactionRemoveAll(eventStr)
 	case "TYPING_START":
 		c.handleTypingStart(eventStr)
 	case "PRESENCE_UPDATE":
@@ -517,13 +873,21 @@ func (c *Client) handleEvent(eventStr []byte) {
 		c.handleChannelUpdate(eventStr)
 	case "CHANNEL_DELETE":
 		c.handleChannelDelete(eventStr)
+	case "CHANNEL_PINS_UPDATE":
+		c.handleChannelPinsUpdate(eventStr)
 	case "GUILD_CREATE":
 		c.handleGuildCreate(eventStr)
 	case "GUILD_DELETE":
 		c.handleGuildDelete(eventStr)
+	case "GUILD_EMOJIS_UPDATE":
+		c.handleGuildEmojisUpdate(eventStr)
+	case "GUILD_BAN_ADD":
+		c.handleGuildBanAdd(eventStr)
+	case "GUILD_BAN_REMOVE":
+		c.handleGuildBanRemove(eventStr)
 	case "GUILD_MEMBER_ADD":
 		c.handleGuildMemberAdd(eventStr)
-	case "GUILD_MEMBER_DELETE":
+	case "GUILD_MEMBER_REMOVE", "GUILD_MEMBER_DELETE":
 		c.handleGuildMemberDelete(eventStr)
 	default:
 		if c.Debug {
@@ -534,46 +898,123 @@ func (c *Client) handleEvent(eventStr []byte) {
 
 }
 
-func (c *Client) getChannelIndex(channelID string) int {
-	var channelPos int
-	for _, server := range c.Servers {
-		for i, channel := range server.Channels {
-			if channel.ID == channelID {
-				channelPos = i
-				break
-			}
+// getChannelIndex returns the position of the channel in its server, or -1
+// when it is not cached. serversLock must be held.
+func (c *Client) getChannelIndex(serverID string, channelID string) int {
+	for i, channel := range c.Servers[serverID].Channels {
+		if channel.ID == channelID {
+			return i
 		}
 	}
-	return channelPos
+	return -1
 }
 
-func (c *Client) getMemberIndex(memberID string) int {
-	var memberPos int
-	for _, server := range c.Servers {
-		for i, member := range server.Members {
-			if member.User.ID == memberID {
-				memberPos = i
-				break
-			}
+// uncacheChannel removes a deleted channel from its server
+func (c *Client) uncacheChannel(serverID string, channelID string) {
+	// Get channel id in slice of server
+	c.serversLock.Lock()
+	if i := c.getChannelIndex(serverID, channelID); i >= 0 {
+		// XXX: Workaround for c.Servers[channel.ServerID].Channels = ...
+		// https://github.com/golang/go/issues/3117
+		tmp := c.Servers[serverID]
+		tmp.Channels = append(tmp.Channels[:i], tmp.Channels[i+1:]...)
+		c.Servers[serverID] = tmp
+	}
+	c.serversLock.Unlock()
+	c.messagesLock.Lock()
+	delete(c.Messages, channelID)
+	c.messagesLock.Unlock()
+}
+
+// getMemberIndex returns the position of the member in its server, or -1
+// when it is not cached. serversLock must be held.
+func (c *Client) getMemberIndex(serverID string, memberID string) int {
+	for i, member := range c.Servers[serverID].Members {
+		if member.User.ID == memberID {
+			return i
+		}
+	}
+	return -1
+}
+
+// getMessageIndex returns the position of the message in the cache of its
+// channel, or -1 when it is not cached. messagesLock must be held.
+func (c *Client) getMessageIndex(channelID string, messageID string) int {
+	for i, message := range c.Messages[channelID] {
+		if message.ID == messageID {
+			return i
 		}
 	}
-	return memberPos
+	return -1
+}
+
+// cacheMessage appends the message to the cache of its channel, dropping
+// the oldest ones past MaxMessages
+func (c *Client) cacheMessage(message Message) {
+	c.messagesLock.Lock()
+	defer c.messagesLock.Unlock()
+
+	if c.MaxMessages <= 0 || c.Messages == nil {
+		return
+	}
+	messages := append(c.Messages[message.ChannelID], message)
+	if len(messages) > c.MaxMessages {
+		messages = messages[len(messages)-c.MaxMessages:]
+	}
+	c.Messages[message.ChannelID] = messages
+}
+
+// updateCachedMessage applies a MESSAGE_UPDATE to the cached message
+func (c *Client) updateCachedMessage(message Message) {
+	c.messagesLock.Lock()
+	defer c.messagesLock.Unlock()
+
+	i := c.getMessageIndex(message.ChannelID, message.ID)
+	if i < 0 {
+		return
+	}
+	cached := &c.Messages[message.ChannelID][i]
+	// Updates without an author only carry new embeds
+	if message.Author.ID == "" {
+		cached.Embeds = message.Embeds
+		return
+	}
+	if message.Reactions == nil {
+		message.Reactions = cached.Reactions
+	}
+	*cached = message
+}
+
+// uncacheMessage removes a deleted message from the cache
+func (c *Client) uncacheMessage(channelID string, messageID string) {
+	c.messagesLock.Lock()
+	defer c.messagesLock.Unlock()
+
+	if i := c.getMessageIndex(channelID, messageID); i >= 0 {
+		messages := c.Messages[channelID]
+		c.Messages[channelID] = append(messages[:i], messages[i+1:]...)
+	}
 }
 
 // Get sends a GET request to the given url
 func (c *Client) get(url string) ([]byte, error) {
+	return c.getContext(context.Background(), url)
+}
+
+// getContext sends a GET request which is abandoned when ctx is done
+func (c *Client) getContext(ctx context.Context, url string) ([]byte, error) {
 	// Prepare request
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Authorization", c.token.Value)
+	req = req.WithContext(ctx)
 
 	return c.doRequest(req)
 }
 
 // Post sends a POST request with payload to the given url
-func (c *Client) request(method string, url string, payload interface{}) ([]byte, error) {
+func (c *Client) request(method string, url string, payload interface{}, opts ...RequestOption) ([]byte, error) {
 	payloadJSON, _ := json.Marshal(payload)
 	contentReader := bytes.NewReader(payloadJSON)
 
@@ -582,41 +1023,212 @@ func (c *Client) request(method string, url string, payload interface{}) ([]byte
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Authorization", c.token.Value)
 	req.Header.Set("Content-Type", "application/json")
+	for _, opt := range opts {
+		opt(req)
+	}
 
 	return c.doRequest(req)
 }
 
-// Login initialize Discord connection by requesting a token
-func (c *Client) Login(email string, password string) error {
-	// Prepare POST json
-	m := map[string]string{
-		"email":    email,
-		"password": password,
+// RequestOption alters a request before it is sent, every call recorded in
+// the audit log of a server accepts them
+type RequestOption func(req *http.Request)
+
+// WithReason records the reason of an action in the server's audit log
+func WithReason(reason string) RequestOption {
+	return func(req *http.Request) {
+		if reason != "" {
+			// Headers can't hold anything but ASCII
+			req.Header.Set("X-Audit-Log-Reason", url.PathEscape(reason))
+		}
 	}
+}
 
-	// Get token
-	tokenResp, err := c.request("POST", apiLogin, m)
+// requestMultipart sends the JSON payload as "payload_json" along with the
+// given files in a multipart/form-data body
+func (c *Client) requestMultipart(method string, url string, payload interface{}, files []File) ([]byte, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	payloadJSON, err := json.Marshal(payload)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if err := json.Unmarshal(tokenResp, &c.token); err != nil {
-		return err
+	if err := writer.WriteField("payload_json", string(payloadJSON)); err != nil {
+		return nil, err
 	}
 
-	// Get websocket gateway
-	gatewayResp, err := c.get(apiGateway)
-	if err != nil {
+	for i, file := range files {
+		header := make(textproto.MIMEHeader)
+		header.Set(
+			"Content-Disposition",
+			fmt.Sprintf(`form-data; name="file%d"; filename="%s"`, i, quoteEscaper.Replace(file.Name)),
+		)
+		contentType := file.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		header.Set("Content-Type", contentType)
+
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.Copy(part, file.Reader); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	// Prepare request
+	req, err := http.NewRequest(method, url, bytes.NewReader(body.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	return c.doRequest(req)
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// Login initialize Discord connection by requesting a token.
+// When TokenFile is set, the token saved there is tried first and
+// credentials are only sent if Discord rejects it.
+func (c *Client) Login(email string, password string) error {
+	loggedIn := false
+	if c.TokenFile != "" {
+		var err error
+		if loggedIn, err = c.loginFromTokenFile(); err != nil {
+			return err
+		}
+	}
+
+	if !loggedIn {
+		if err := c.loginWithCredentials(email, password); err != nil {
+			return err
+		}
+		if c.TokenFile != "" {
+			if err := saveToken(c.TokenFile, c.token.Value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return c.fetchGateway()
+}
+
+// fetchGateway gets the websocket gateway once a token is known
+func (c *Client) fetchGateway() error {
+	gatewayResp, err := c.get(apiGateway)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(gatewayResp, &c.gateway)
+}
+
+func (c *Client) loginWithCredentials(email string, password string) error {
+	// Prepare POST json
+	m := map[string]string{
+		"email":    email,
+		"password": password,
+	}
+
+	// Get token
+	tokenResp, err := c.request("POST", apiLogin, m)
+	if err != nil {
 		return err
 	}
-	if err := json.Unmarshal(gatewayResp, &c.gateway); err != nil {
+	return json.Unmarshal(tokenResp, &c.token)
+}
+
+// loginFromTokenFile validates the saved token against the API, a missing
+// file or a rejected token is not an error but returns false
+func (c *Client) loginFromTokenFile() (bool, error) {
+	token, err := loadToken(c.TokenFile)
+	if os.IsNotExist(err) || (err == nil && token == "") {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	c.token.Value = token
+	response, err := c.get(apiUsers + "/@me")
+	if isUnauthorized(err) {
+		log.Print("Saved token rejected, logging in with credentials")
+		c.token.Value = ""
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if err := json.Unmarshal(response, &c.User); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// LoginWithToken authenticates the client with an already obtained token,
+// such as a bot token or an OAuth2 "Bearer" access token
+func (c *Client) LoginWithToken(token string) error {
+	c.token.Value = token
+	return c.fetchGateway()
+}
+
+// Logout invalidates the current token and closes the websocket connection
+func (c *Client) Logout() error {
+	_, err := c.request(
+		"POST",
+		apiLogout,
+		map[string]string{
+			"token": c.token.Value,
+		},
+	)
+	if err != nil {
 		return err
 	}
 
+	if c.wsConn != nil {
+		c.Stop()
+	}
+	c.token.Value = ""
+
+	// The saved token is no longer valid
+	if c.TokenFile != "" {
+		if err := os.Remove(c.TokenFile); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// Register creates a new account and logs the client in with it
+func (c *Client) Register(email string, username string, password string) error {
+	tokenResp, err := c.request(
+		"POST",
+		apiRegister,
+		map[string]string{
+			"email":    email,
+			"username": username,
+			"password": password,
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(tokenResp, &c.token); err != nil {
+		return err
+	}
+
+	return c.fetchGateway()
+}
+
 // LoginFromFile call login with email and password found in the given file
 func (c *Client) LoginFromFile(filename string) error {
 	fileDump, err := ioutil.ReadFile(filename)
@@ -666,6 +1278,9 @@ func (c *Client) GetChannel(server Server, channelName string) Channel {
 
 // GetChannelByID returns the Channel object from the given ID as well as its position in its server's channel list
 func (c *Client) GetChannelByID(channelID string) Channel {
+	c.serversLock.RLock()
+	defer c.serversLock.RUnlock()
+
 	var res Channel
 	for _, server := range c.Servers {
 		for _, channel := range server.Channels {
@@ -678,8 +1293,52 @@ func (c *Client) GetChannelByID(channelID string) Channel {
 	return res
 }
 
+// isServerChannel tells whether the channel belongs to a server, private
+// and group channels do not support bulk deletes. Channels missing from the
+// cache are fetched.
+func (c *Client) isServerChannel(channel Messageable) (bool, error) {
+	switch ch := channel.(type) {
+	case Channel:
+		if ch.ServerID != "" {
+			return true, nil
+		}
+	case *Channel:
+		if ch.ServerID != "" {
+			return true, nil
+		}
+	}
+
+	channelID := channel.GetChannelID()
+	if c.GetChannelByID(channelID).ID != "" {
+		return true, nil
+	}
+	c.serversLock.RLock()
+	_, private := c.PrivateChannels[channelID]
+	c.serversLock.RUnlock()
+	if private {
+		return false, nil
+	}
+
+	fetched, err := c.FetchChannel(channelID)
+	if err != nil {
+		return false, err
+	}
+	return fetched.ServerID != "", nil
+}
+
+// GetServerByID returns the Server object from the given server ID
+func (c *Client) GetServerByID(serverID string) Server {
+	c.serversLock.RLock()
+	defer c.serversLock.RUnlock()
+
+	return c.Servers[serverID]
+}
+
 // GetServer returns the Server object from the given server name
 func (c *Client) GetServer(serverName string) Server {
+	c.serversLock.RLock()
+	defer c.serversLock.RUnlock()
+
 	var res Server
 	for _, server := range c.Servers {
 		if server.Name == serverName {
@@ -690,11 +1349,12 @@ func (c *Client) GetServer(serverName string) Server {
 	return res
 }
 
-// GetUser returns the User object on the specified server using the given name
+// GetUser returns the User object on the specified server using the given
+// nickname or username
 func (c *Client) GetUser(server Server, userName string) User {
 	var res User
 	for _, member := range server.Members {
-		if member.User.Name == userName {
+		if member.DisplayName() == userName || member.User.Name == userName {
 			res = member.User
 			break
 		}
@@ -704,6 +1364,9 @@ func (c *Client) GetUser(server Server, userName string) User {
 
 // GetUserByID returns the User object from the given user ID
 func (c *Client) GetUserByID(userID string) User {
+	c.serversLock.RLock()
+	defer c.serversLock.RUnlock()
+
 	var res User
 	for _, server := range c.Servers {
 		for _, member := range server.Members {
@@ -716,28 +1379,166 @@ func (c *Client) GetUserByID(userID string) User {
 	return res
 }
 
-// JoinServer receive an invite ID and tries to join the corresponding server/channel
+// JoinServer receive an invite ID (or URL) and tries to join the corresponding server/channel
 func (c *Client) JoinServer(inviteID string) error {
 	_, err := c.request(
 		"POST",
-		fmt.Sprintf("%s/%s", apiInvite, inviteID),
+		fmt.Sprintf("%s/%s", apiInvite, ParseInviteCode(inviteID)),
 		nil,
 	)
 	return err
 }
 
-// SendMessage sends a message to the given channel
-// XXX: string sent as channel ID because of Channel/PrivateChannel differences
-func (c *Client) SendMessage(channelID string, content string) (Message, error) {
-	var message Message
+// CreateServer creates a new server owned by the client, icon may be nil
+func (c *Client) CreateServer(name string, region string, icon []byte) (Server, error) {
+	var server Server
+
+	m := map[string]interface{}{
+		"name":   name,
+		"region": region,
+	}
+	if icon != nil {
+		m["icon"] = imageDataURI(icon)
+	}
+
+	response, err := c.request("POST", apiServers, m)
+	if err != nil {
+		return server, err
+	}
+
+	if err := json.Unmarshal(response, &server); err != nil {
+		return server, err
+	}
+
+	// GUILD_CREATE follows with the full server
+	c.serversLock.Lock()
+	if _, ok := c.Servers[server.ID]; !ok && c.Servers != nil {
+		c.Servers[server.ID] = server
+	}
+	c.serversLock.Unlock()
+
+	return server, nil
+}
+
+// EditServer changes the settings of the server
+func (c *Client) EditServer(serverID string, params ServerParams, opts ...RequestOption) (Server, error) {
+	var edited Server
+
+	if err := c.checkServerPermissions(serverID, PermissionManageServer); err != nil {
+		return edited, err
+	}
+
+	m := make(map[string]interface{})
+	if params.Name != "" {
+		m["name"] = params.Name
+	}
+	if params.Region != "" {
+		m["region"] = params.Region
+	}
+	if params.AfkChannelID != "" {
+		m["afk_channel_id"] = params.AfkChannelID
+	}
+	if params.AfkTimeout > 0 {
+		m["afk_timeout"] = params.AfkTimeout
+	}
+	if params.Icon != nil {
+		m["icon"] = imageDataURI(params.Icon)
+	}
+	if params.OwnerID != "" {
+		m["owner_id"] = params.OwnerID
+	}
+	if params.VerificationLevel != nil {
+		m["verification_level"] = *params.VerificationLevel
+	}
 
 	response, err := c.request(
-		"POST",
-		fmt.Sprintf(apiChannels+"/%s/messages", channelID),
-		map[string]string{
-			"content": content,
-		},
+		"PATCH",
+		fmt.Sprintf("%s/%s", apiServers, serverID),
+		m,
+		opts...,
+	)
+	if err != nil {
+		return edited, err
+	}
+
+	if err := json.Unmarshal(response, &edited); err != nil {
+		return edited, err
+	}
+
+	// The response doesn't hold members, channels nor presences
+	c.serversLock.Lock()
+	if tmp, ok := c.Servers[serverID]; ok {
+		edited.Members = tmp.Members
+		edited.Channels = tmp.Channels
+		edited.Presences = tmp.Presences
+		edited.JoinedAt = tmp.JoinedAt
+		edited.Large = tmp.Large
+		c.Servers[serverID] = edited
+	}
+	c.serversLock.Unlock()
+
+	return edited, nil
+}
+
+// LeaveServer makes the client leave the server
+func (c *Client) LeaveServer(serverID string) error {
+	_, err := c.request(
+		"DELETE",
+		fmt.Sprintf("%s/@me/guilds/%s", apiUsers, serverID),
+		nil,
 	)
+	if err != nil {
+		return err
+	}
+
+	c.serversLock.Lock()
+	delete(c.Servers, serverID)
+	c.serversLock.Unlock()
+	return nil
+}
+
+// DeleteServer deletes the server, only its owner can do it
+func (c *Client) DeleteServer(serverID string) error {
+	_, err := c.request(
+		"DELETE",
+		fmt.Sprintf("%s/%s", apiServers, serverID),
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	c.serversLock.Lock()
+	delete(c.Servers, serverID)
+	c.serversLock.Unlock()
+	return nil
+}
+
+// SendMessage sends a message to the given channel
+func (c *Client) SendMessage(channel Messageable, content string) (Message, error) {
+	return c.SendMessageComplex(channel, MessageParams{Content: content})
+}
+
+// SendMessageComplex sends a message with text-to-speech, an embed or
+// attached files to the given channel
+func (c *Client) SendMessageComplex(channel Messageable, params MessageParams) (Message, error) {
+	channelID := channel.GetChannelID()
+	var message Message
+	var response []byte
+	var err error
+
+	if params.Embed != nil {
+		if err := params.Embed.Validate(); err != nil {
+			return message, err
+		}
+	}
+
+	url := fmt.Sprintf("%s/%s/messages", apiChannels, channelID)
+	if len(params.Files) > 0 {
+		response, err = c.requestMultipart("POST", url, params, params.Files)
+	} else {
+		response, err = c.request("POST", url, params)
+	}
 	if err != nil {
 		return message, err
 	}
@@ -749,10 +1550,145 @@ func (c *Client) SendMessage(channelID string, content string) (Message, error)
 	return message, err
 }
 
+// ChannelMessages returns at most limit (up to 100) messages of the channel,
+// newest first. Only one of before, after and around is used by Discord,
+// leave them empty to get the latest messages.
+func (c *Client) ChannelMessages(channel Messageable, before string, after string, around string, limit int) ([]Message, error) {
+	return c.channelMessages(context.Background(), channel.GetChannelID(), before, after, around, limit)
+}
+
+func (c *Client) channelMessages(ctx context.Context, channelID string, before string, after string, around string, limit int) ([]Message, error) {
+	var messages []Message
+
+	v := url.Values{}
+	if before != "" {
+		v.Set("before", before)
+	}
+	if after != "" {
+		v.Set("after", after)
+	}
+	if around != "" {
+		v.Set("around", around)
+	}
+	if limit > 0 {
+		v.Set("limit", strconv.Itoa(limit))
+	}
+
+	u := fmt.Sprintf("%s/%s/messages", apiChannels, channelID)
+	if len(v) > 0 {
+		u += "?" + v.Encode()
+	}
+
+	response, err := c.getContext(ctx, u)
+	if err != nil {
+		return messages, err
+	}
+
+	if err := json.Unmarshal(response, &messages); err != nil {
+		return messages, err
+	}
+
+	return messages, nil
+}
+
+// ChannelHistory returns an iterator over every message of the channel,
+// from the newest to the oldest
+func (c *Client) ChannelHistory(channel Messageable) *MessageIterator {
+	return &MessageIterator{
+		client:    c,
+		channelID: channel.GetChannelID(),
+	}
+}
+
+// GetMessageByID returns the message from the cache, see MaxMessages
+func (c *Client) GetMessageByID(channel Messageable, messageID string) Message {
+	channelID := channel.GetChannelID()
+	var res Message
+	c.messagesLock.RLock()
+	if i := c.getMessageIndex(channelID, messageID); i >= 0 {
+		res = c.Messages[channelID][i]
+	}
+	c.messagesLock.RUnlock()
+	return res
+}
+
+// AddReaction reacts to a message with the given emoji, either unicode or
+// "name:id" for custom ones (see Emoji.APIName)
+func (c *Client) AddReaction(channel Messageable, messageID string, emoji string) error {
+	channelID := channel.GetChannelID()
+
+	_, err := c.request(
+		"PUT",
+		fmt.Sprintf("%s/%s/messages/%s/reactions/%s/@me", apiChannels, channelID, messageID, escapeEmoji(emoji)),
+		nil,
+	)
+	return err
+}
+
+// RemoveReaction removes the reaction of the given user to a message,
+// an empty userID removes the client's own reaction
+func (c *Client) RemoveReaction(channel Messageable, messageID string, emoji string, userID string) error {
+	channelID := channel.GetChannelID()
+
+	if userID == "" {
+		userID = "@me"
+	}
+	_, err := c.request(
+		"DELETE",
+		fmt.Sprintf("%s/%s/messages/%s/reactions/%s/%s", apiChannels, channelID, messageID, escapeEmoji(emoji), userID),
+		nil,
+	)
+	return err
+}
+
+// RemoveAllReactions removes every reaction of a message
+func (c *Client) RemoveAllReactions(channel Messageable, messageID string) error {
+	channelID := channel.GetChannelID()
+
+	_, err := c.request(
+		"DELETE",
+		fmt.Sprintf("%s/%s/messages/%s/reactions", apiChannels, channelID, messageID),
+		nil,
+	)
+	return err
+}
+
+// Reactions returns at most limit (up to 100) users who reacted to a message
+// with the given emoji, after is the last user ID of the previous page
+func (c *Client) Reactions(channel Messageable, messageID string, emoji string, after string, limit int) ([]User, error) {
+	channelID := channel.GetChannelID()
+	var users []User
+
+	v := url.Values{}
+	if after != "" {
+		v.Set("after", after)
+	}
+	if limit > 0 {
+		v.Set("limit", strconv.Itoa(limit))
+	}
+
+	u := fmt.Sprintf("%s/%s/messages/%s/reactions/%s", apiChannels, channelID, messageID, escapeEmoji(emoji))
+	if len(v) > 0 {
+		u += "?" + v.Encode()
+	}
+
+	response, err := c.get(u)
+	if err != nil {
+		return users, err
+	}
+
+	if err := json.Unmarshal(response, &users); err != nil {
+		return users, err
+	}
+
+	return users, nil
+}
+
 // GetPrivateChannel returns the private channel corresponding to the user
 func (c *Client) GetPrivateChannel(user User) (pc PrivateChannel) {
 	found := false
 
+	c.serversLock.RLock()
 	for _, private := range c.PrivateChannels {
 		if private.Recipient.ID == user.ID {
 			pc = private
@@ -760,6 +1696,7 @@ func (c *Client) GetPrivateChannel(user User) (pc PrivateChannel) {
 			break
 		}
 	}
+	c.serversLock.RUnlock()
 
 	if !found {
 		pc, _ = c.CreatePrivateChannel(user)
@@ -788,23 +1725,76 @@ func (c *Client) CreatePrivateChannel(user User) (PrivateChannel, error) {
 		return pChannel, err
 	}
 
+	c.serversLock.Lock()
 	c.PrivateChannels[pChannel.ID] = pChannel
+	c.serversLock.Unlock()
 
 	return pChannel, err
 }
 
+// EditProfile changes the account of the logged in user and keeps
+// Client.User in sync with the result
+func (c *Client) EditProfile(params ProfileParams) (User, error) {
+	// Discord expects the whole profile, start from the current one
+	m := map[string]interface{}{
+		"username": c.User.Name,
+		"email":    c.User.Email,
+		"avatar":   c.User.Avatar,
+		"password": params.Password,
+	}
+	if params.Username != "" {
+		m["username"] = params.Username
+	}
+	if params.Email != "" {
+		m["email"] = params.Email
+	}
+	if params.NewPassword != "" {
+		m["new_password"] = params.NewPassword
+	}
+	if params.Avatar != nil {
+		m["avatar"] = imageDataURI(params.Avatar)
+	}
+
+	response, err := c.request("PATCH", apiUsers+"/@me", m)
+	if err != nil {
+		return c.User, err
+	}
+
+	// A new token is issued when the password changes
+	var profile struct {
+		User
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(response, &profile); err != nil {
+		return c.User, err
+	}
+
+	c.User = profile.User
+	if profile.Token != "" {
+		c.token.Value = profile.Token
+		if c.TokenFile != "" {
+			if err := saveToken(c.TokenFile, profile.Token); err != nil {
+				return c.User, err
+			}
+		}
+	}
+
+	return c.User, nil
+}
+
 // AckMessage acknowledges the message on the given channel
-func (c *Client) AckMessage(channel Channel, message Message) error {
+func (c *Client) AckMessage(channel Messageable, message Message) error {
 	_, err := c.request(
 		"POST",
-		fmt.Sprintf("%s/%s/messages/%s/ack", apiChannels, channel.ID, message.ID),
+		fmt.Sprintf("%s/%s/messages/%s/ack", apiChannels, channel.GetChannelID(), message.ID),
 		nil,
 	)
 	return err
 }
 
 // EditMessage modifies the message from the channel with the given ID
-func (c *Client) EditMessage(channelID string, messageID string, content string) (Message, error) {
+func (c *Client) EditMessage(channel Messageable, messageID string, content string) (Message, error) {
+	channelID := channel.GetChannelID()
 	var message Message
 
 	response, err := c.request(
@@ -825,70 +1815,876 @@ func (c *Client) EditMessage(channelID string, messageID string, content string)
 	return message, err
 }
 
-// DeleteMessage deletes the message from the channel with the given ID
-func (c *Client) DeleteMessage(channel Channel, message Message) error {
-	_, err := c.request(
-		"DELETE",
-		fmt.Sprintf("%s/%s/messages/%s", apiChannels, channel.ID, message.ID),
-		nil,
+// EditMessageComplex modifies the content and embed of the message from the
+// channel with the given ID, empty fields are left untouched
+func (c *Client) EditMessageComplex(channel Messageable, messageID string, params MessageParams) (Message, error) {
+	channelID := channel.GetChannelID()
+	var message Message
+
+	if params.Embed != nil {
+		if err := params.Embed.Validate(); err != nil {
+			return message, err
+		}
+	}
+
+	response, err := c.request(
+		"PATCH",
+		fmt.Sprintf("%s/%s/messages/%s", apiChannels, channelID, messageID),
+		params,
+	)
+	if err != nil {
+		return message, err
+	}
+
+	if err := json.Unmarshal(response, &message); err != nil {
+		return message, err
+	}
+
+	return message, err
+}
+
+// DeleteMessage deletes the message from the channel with the given ID
+func (c *Client) DeleteMessage(channel Messageable, message Message, opts ...RequestOption) error {
+	// Anyone can delete their own messages
+	if message.Author.ID != c.User.ID {
+		if err := c.checkChannelPermissions(channel.GetChannelID(), PermissionManageMessages); err != nil {
+			return err
+		}
+	}
+	return c.deleteMessage(channel.GetChannelID(), message.ID, opts...)
+}
+
+func (c *Client) deleteMessage(channelID string, messageID string, opts ...RequestOption) error {
+	_, err := c.request(
+		"DELETE",
+		fmt.Sprintf("%s/%s/messages/%s", apiChannels, channelID, messageID),
+		nil,
+		opts...,
 	)
 	return err
 }
 
-// Ban bans a user from the giver server
-func (c *Client) Ban(server Server, user User) error {
+// BulkDeleteMessages deletes the messages of the channel with the given IDs,
+// 100 at a time. Discord refuses to bulk delete messages older than
+// BulkDeleteMaxAge, use Purge to handle them. Outside of servers, messages
+// are deleted one by one and only the client's own can be.
+func (c *Client) BulkDeleteMessages(channel Messageable, messageIDs []string, opts ...RequestOption) error {
+	channelID := channel.GetChannelID()
+
+	serverChannel, err := c.isServerChannel(channel)
+	if err != nil {
+		return err
+	}
+	if !serverChannel {
+		for _, messageID := range messageIDs {
+			if err := c.deleteMessage(channelID, messageID, opts...); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return c.bulkDeleteMessages(channelID, messageIDs, opts...)
+}
+
+func (c *Client) bulkDeleteMessages(channelID string, messageIDs []string, opts ...RequestOption) error {
+	if err := c.checkChannelPermissions(channelID, PermissionManageMessages); err != nil {
+		return err
+	}
+
+	for len(messageIDs) > 0 {
+		n := len(messageIDs)
+		if n > bulkDeleteLimit {
+			n = bulkDeleteLimit
+		}
+		chunk := messageIDs[:n]
+		messageIDs = messageIDs[n:]
+
+		// Bulk delete needs at least 2 messages
+		if len(chunk) == 1 {
+			if err := c.deleteMessage(channelID, chunk[0], opts...); err != nil {
+				return err
+			}
+			continue
+		}
+
+		_, err := c.request(
+			"POST",
+			fmt.Sprintf("%s/%s/messages/bulk-delete", apiChannels, channelID),
+			map[string][]string{
+				"messages": chunk,
+			},
+			opts...,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Purge walks the history of the channel and deletes at most limit messages
+// matching the filter (0 for no limit). Recent messages are bulk deleted,
+// older ones one by one. Outside of servers, only the client's own messages
+// can be deleted and always one by one. It returns the number of deleted
+// messages.
+func (c *Client) Purge(channel Messageable, filter PurgeFilter, limit int, opts ...RequestOption) (int, error) {
+	channelID := channel.GetChannelID()
+	serverChannel, err := c.isServerChannel(channel)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	var batch []string
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		err := c.bulkDeleteMessages(channelID, batch, opts...)
+		if err == nil {
+			deleted += len(batch)
+		}
+		batch = batch[:0]
+		return err
+	}
+
+	it := c.ChannelHistory(channel)
+	for (limit <= 0 || deleted+len(batch) < limit) && it.Next(context.Background()) {
+		message := it.Message()
+		age := time.Since(message.Time())
+
+		// History goes backwards, nothing older can match
+		if filter.NewerThan > 0 && age > filter.NewerThan {
+			break
+		}
+		if !filter.Match(message) {
+			continue
+		}
+		if !serverChannel && message.Author.ID != c.User.ID {
+			continue
+		}
+
+		if serverChannel && age < BulkDeleteMaxAge-bulkDeleteMargin {
+			batch = append(batch, message.ID)
+			if len(batch) == bulkDeleteLimit {
+				if err := flush(); err != nil {
+					return deleted, err
+				}
+			}
+		} else {
+			// Only older messages follow, delete the recent ones before
+			// they get too old as well
+			if err := flush(); err != nil {
+				return deleted, err
+			}
+			if err := c.deleteMessage(channelID, message.ID, opts...); err != nil {
+				return deleted, err
+			}
+			deleted++
+		}
+	}
+	if err := it.Err(); err != nil {
+		return deleted, err
+	}
+
+	if err := flush(); err != nil {
+		return deleted, err
+	}
+	return deleted, nil
+}
+
+// TriggerTyping shows the client as typing in the channel for a few seconds
+// or until it sends a message
+func (c *Client) TriggerTyping(channel Messageable) error {
+	channelID := channel.GetChannelID()
+
+	_, err := c.request(
+		"POST",
+		fmt.Sprintf("%s/%s/typing", apiChannels, channelID),
+		nil,
+	)
+	return err
+}
+
+// Typing keeps the typing indicator alive in the channel until ctx is done,
+// run it in its own goroutine around long operations:
+//
+//	ctx, cancel := context.WithCancel(context.Background())
+//	go client.Typing(ctx, channel)
+//	defer cancel()
+func (c *Client) Typing(ctx context.Context, channel Messageable) error {
+	ticker := time.NewTicker(typingInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.TriggerTyping(channel); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// PinMessage pins the message in its channel
+func (c *Client) PinMessage(channel Messageable, messageID string) error {
+	channelID := channel.GetChannelID()
+
 	_, err := c.request(
 		"PUT",
-		fmt.Sprintf("%s/%s/bans/%s", apiServers, server.ID, user.ID),
+		fmt.Sprintf("%s/%s/pins/%s", apiChannels, channelID, messageID),
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	c.messagesLock.Lock()
+	if i := c.getMessageIndex(channelID, messageID); i >= 0 {
+		c.Messages[channelID][i].Pinned = true
+	}
+	c.messagesLock.Unlock()
+	return nil
+}
+
+// UnpinMessage unpins the message from its channel
+func (c *Client) UnpinMessage(channel Messageable, messageID string) error {
+	channelID := channel.GetChannelID()
+
+	_, err := c.request(
+		"DELETE",
+		fmt.Sprintf("%s/%s/pins/%s", apiChannels, channelID, messageID),
 		nil,
 	)
+	if err != nil {
+		return err
+	}
+
+	c.messagesLock.Lock()
+	if i := c.getMessageIndex(channelID, messageID); i >= 0 {
+		c.Messages[channelID][i].Pinned = false
+	}
+	c.messagesLock.Unlock()
+	return nil
+}
+
+// PinnedMessages returns the pinned messages of the channel
+func (c *Client) PinnedMessages(channel Messageable) ([]Message, error) {
+	channelID := channel.GetChannelID()
+	var messages []Message
+
+	response, err := c.get(fmt.Sprintf("%s/%s/pins", apiChannels, channelID))
+	if err != nil {
+		return messages, err
+	}
+
+	if err := json.Unmarshal(response, &messages); err != nil {
+		return messages, err
+	}
+
+	return messages, nil
+}
+
+// Ban bans a user from the giver server
+func (c *Client) Ban(server Server, user User, opts ...RequestOption) error {
+	return c.BanWithParams(server.ID, user.ID, BanParams{}, opts...)
+}
+
+// BanWithParams bans a user from the given server, deleting its recent
+// messages and recording the reason in the audit log
+func (c *Client) BanWithParams(serverID string, userID string, params BanParams, opts ...RequestOption) error {
+	if err := c.checkServerPermissions(serverID, PermissionBanMembers); err != nil {
+		return err
+	}
+
+	u := fmt.Sprintf("%s/%s/bans/%s", apiServers, serverID, userID)
+	if params.DeleteMessageDays > 0 {
+		u += "?delete-message-days=" + strconv.Itoa(params.DeleteMessageDays)
+	}
+
+	opts = append([]RequestOption{WithReason(params.Reason)}, opts...)
+	_, err := c.request("PUT", u, nil, opts...)
 	return err
 }
 
 // Unban unbans a user from the giver server
-func (c *Client) Unban(server Server, user User) error {
+func (c *Client) Unban(server Server, user User, opts ...RequestOption) error {
+	return c.UnbanByID(server.ID, user.ID, opts...)
+}
+
+// UnbanByID unbans the user with the given ID, which may not be cached
+// anymore (see Bans)
+func (c *Client) UnbanByID(serverID string, userID string, opts ...RequestOption) error {
+	if err := c.checkServerPermissions(serverID, PermissionBanMembers); err != nil {
+		return err
+	}
+
 	_, err := c.request(
 		"DELETE",
-		fmt.Sprintf("%s/%s/bans/%s", apiServers, server.ID, user.ID),
+		fmt.Sprintf("%s/%s/bans/%s", apiServers, serverID, userID),
 		nil,
+		opts...,
 	)
 	return err
 }
 
+// Bans returns the banned users of the server along with the ban reasons
+func (c *Client) Bans(serverID string) ([]Ban, error) {
+	var bans []Ban
+
+	if err := c.checkServerPermissions(serverID, PermissionBanMembers); err != nil {
+		return bans, err
+	}
+
+	response, err := c.get(fmt.Sprintf("%s/%s/bans", apiServers, serverID))
+	if err != nil {
+		return bans, err
+	}
+
+	if err := json.Unmarshal(response, &bans); err != nil {
+		return bans, err
+	}
+
+	for i := range bans {
+		bans[i].ServerID = serverID
+	}
+
+	return bans, nil
+}
+
 // Kick kicks a user from the giver server
-func (c *Client) Kick(server Server, user User) error {
+func (c *Client) Kick(server Server, user User, opts ...RequestOption) error {
+	if err := c.checkServerPermissions(server.ID, PermissionKickMembers); err != nil {
+		return err
+	}
+
 	_, err := c.request(
 		"DELETE",
 		fmt.Sprintf("%s/%s/members/%s", apiServers, server.ID, user.ID),
 		nil,
+		opts...,
 	)
 	return err
 }
 
-// CreateChannel creates a new channel in the given server
-func (c *Client) CreateChannel(server Server, name string, channelType string) error {
-	_, err := c.request(
+// CreateRole creates a new role with default settings in the given server,
+// use EditRole to configure it
+func (c *Client) CreateRole(serverID string, opts ...RequestOption) (Role, error) {
+	var role Role
+
+	response, err := c.request(
 		"POST",
-		fmt.Sprintf("%s/%s/channels", apiServers, server.ID),
+		fmt.Sprintf("%s/%s/roles", apiServers, serverID),
+		nil,
+		opts...,
+	)
+	if err != nil {
+		return role, err
+	}
+
+	if err := json.Unmarshal(response, &role); err != nil {
+		return role, err
+	}
+
+	// https://github.com/golang/go/issues/3117
+	c.serversLock.Lock()
+	if tmp, ok := c.Servers[serverID]; ok {
+		tmp.Roles = append(tmp.Roles, role)
+		c.Servers[serverID] = tmp
+	}
+	c.serversLock.Unlock()
+
+	return role, nil
+}
+
+// EditRole sends the name, color, hoist, permissions and mentionable
+// settings of the given role
+func (c *Client) EditRole(serverID string, role Role, opts ...RequestOption) (Role, error) {
+	var edited Role
+
+	response, err := c.request(
+		"PATCH",
+		fmt.Sprintf("%s/%s/roles/%s", apiServers, serverID, role.ID),
+		map[string]interface{}{
+			"name":        role.Name,
+			"color":       role.Color,
+			"hoist":       role.Hoist,
+			"permissions": role.Permissions,
+			"mentionable": role.Mentionable,
+		},
+		opts...,
+	)
+	if err != nil {
+		return edited, err
+	}
+
+	if err := json.Unmarshal(response, &edited); err != nil {
+		return edited, err
+	}
+
+	c.serversLock.Lock()
+	if tmp, ok := c.Servers[serverID]; ok {
+		for i := range tmp.Roles {
+			if tmp.Roles[i].ID == edited.ID {
+				tmp.Roles[i] = edited
+				break
+			}
+		}
+	}
+	c.serversLock.Unlock()
+
+	return edited, nil
+}
+
+// DeleteRole deletes the role from the given server
+func (c *Client) DeleteRole(serverID string, roleID string, opts ...RequestOption) error {
+	_, err := c.request(
+		"DELETE",
+		fmt.Sprintf("%s/%s/roles/%s", apiServers, serverID, roleID),
+		nil,
+		opts...,
+	)
+	if err != nil {
+		return err
+	}
+
+	// https://github.com/golang/go/issues/3117
+	c.serversLock.Lock()
+	if tmp, ok := c.Servers[serverID]; ok {
+		for i := range tmp.Roles {
+			if tmp.Roles[i].ID == roleID {
+				tmp.Roles = append(tmp.Roles[:i], tmp.Roles[i+1:]...)
+				break
+			}
+		}
+		for i := range tmp.Members {
+			tmp.Members[i].Roles = removeString(tmp.Members[i].Roles, roleID)
+		}
+		c.Servers[serverID] = tmp
+	}
+	c.serversLock.Unlock()
+
+	return nil
+}
+
+// ReorderRoles sets the position of each role from the order of the given
+// IDs, the first one being the lowest role above @everyone
+func (c *Client) ReorderRoles(serverID string, roleIDs []string, opts ...RequestOption) ([]Role, error) {
+	var roles []Role
+
+	positions := make([]map[string]interface{}, len(roleIDs))
+	for i, id := range roleIDs {
+		positions[i] = map[string]interface{}{
+			"id":       id,
+			"position": i + 1,
+		}
+	}
+
+	response, err := c.request(
+		"PATCH",
+		fmt.Sprintf("%s/%s/roles", apiServers, serverID),
+		positions,
+		opts...,
+	)
+	if err != nil {
+		return roles, err
+	}
+
+	if err := json.Unmarshal(response, &roles); err != nil {
+		return roles, err
+	}
+
+	// https://github.com/golang/go/issues/3117
+	c.serversLock.Lock()
+	if tmp, ok := c.Servers[serverID]; ok {
+		tmp.Roles = roles
+		c.Servers[serverID] = tmp
+	}
+	c.serversLock.Unlock()
+
+	return roles, nil
+}
+
+// AddMemberRole gives the role to a member of the server
+func (c *Client) AddMemberRole(serverID string, userID string, roleID string, opts ...RequestOption) error {
+	_, err := c.request(
+		"PUT",
+		fmt.Sprintf("%s/%s/members/%s/roles/%s", apiServers, serverID, userID, roleID),
+		nil,
+		opts...,
+	)
+	if err != nil {
+		return err
+	}
+
+	c.serversLock.Lock()
+	if tmp, ok := c.Servers[serverID]; ok {
+		for i := range tmp.Members {
+			if tmp.Members[i].User.ID == userID {
+				tmp.Members[i].Roles = append(removeString(tmp.Members[i].Roles, roleID), roleID)
+				break
+			}
+		}
+	}
+	c.serversLock.Unlock()
+
+	return nil
+}
+
+// RemoveMemberRole takes the role away from a member of the server
+func (c *Client) RemoveMemberRole(serverID string, userID string, roleID string, opts ...RequestOption) error {
+	_, err := c.request(
+		"DELETE",
+		fmt.Sprintf("%s/%s/members/%s/roles/%s", apiServers, serverID, userID, roleID),
+		nil,
+		opts...,
+	)
+	if err != nil {
+		return err
+	}
+
+	c.serversLock.Lock()
+	if tmp, ok := c.Servers[serverID]; ok {
+		for i := range tmp.Members {
+			if tmp.Members[i].User.ID == userID {
+				tmp.Members[i].Roles = removeString(tmp.Members[i].Roles, roleID)
+				break
+			}
+		}
+	}
+	c.serversLock.Unlock()
+
+	return nil
+}
+
+// EditMember changes the nickname, roles, mute and deaf state of a member,
+// or moves it to another voice channel
+func (c *Client) EditMember(serverID string, userID string, params MemberParams, opts ...RequestOption) error {
+	var required Permissions
+	if params.Nick != nil {
+		required |= PermissionManageNicknames
+	}
+	if params.Roles != nil {
+		required |= PermissionManageRoles
+	}
+	if params.Mute != nil {
+		required |= PermissionVoiceMuteMembers
+	}
+	if params.Deaf != nil {
+		required |= PermissionVoiceDeafenMembers
+	}
+	if params.ChannelID != "" {
+		required |= PermissionVoiceMoveMembers
+	}
+	if err := c.checkServerPermissions(serverID, required); err != nil {
+		return err
+	}
+
+	_, err := c.request(
+		"PATCH",
+		fmt.Sprintf("%s/%s/members/%s", apiServers, serverID, userID),
+		params,
+		opts...,
+	)
+	if err != nil {
+		return err
+	}
+
+	c.serversLock.Lock()
+	if server, ok := c.Servers[serverID]; ok {
+		if member := server.getMember(userID); member != nil {
+			if params.Nick != nil {
+				member.Nick = *params.Nick
+			}
+			if params.Roles != nil {
+				// removeString filters the cached roles in place
+				member.Roles = append([]string(nil), (*params.Roles)...)
+			}
+			if params.Mute != nil {
+				member.Muted = *params.Mute
+			}
+			if params.Deaf != nil {
+				member.Deafed = *params.Deaf
+			}
+		}
+	}
+	c.serversLock.Unlock()
+
+	return nil
+}
+
+// SetOwnNickname changes the nickname of the client in the server, an empty
+// nick removes it
+func (c *Client) SetOwnNickname(serverID string, nick string, opts ...RequestOption) error {
+	_, err := c.request(
+		"PATCH",
+		fmt.Sprintf("%s/%s/members/@me/nick", apiServers, serverID),
 		map[string]string{
-			"name": name,
-			"type": channelType,
+			"nick": nick,
 		},
+		opts...,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	c.serversLock.Lock()
+	if server, ok := c.Servers[serverID]; ok {
+		if member := server.getMember(c.User.ID); member != nil {
+			member.Nick = nick
+		}
+	}
+	c.serversLock.Unlock()
+
+	return nil
+}
+
+// PruneCount returns the number of members who would be kicked by Prune
+func (c *Client) PruneCount(serverID string, days int) (int, error) {
+	if err := c.checkServerPermissions(serverID, PermissionKickMembers); err != nil {
+		return 0, err
+	}
+
+	response, err := c.get(fmt.Sprintf("%s/%s/prune?days=%d", apiServers, serverID, days))
+	if err != nil {
+		return 0, err
+	}
+
+	var prune pruneResult
+	if err := json.Unmarshal(response, &prune); err != nil {
+		return 0, err
+	}
+
+	return prune.Pruned, nil
+}
+
+// Prune kicks the members without roles who have not been seen for the
+// given number of days, and returns how many were kicked. The cache is
+// updated as the GUILD_MEMBER_REMOVE events arrive.
+func (c *Client) Prune(serverID string, days int, opts ...RequestOption) (int, error) {
+	if err := c.checkServerPermissions(serverID, PermissionKickMembers); err != nil {
+		return 0, err
+	}
+
+	response, err := c.request(
+		"POST",
+		fmt.Sprintf("%s/%s/prune?days=%d", apiServers, serverID, days),
+		nil,
+		opts...,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	var prune pruneResult
+	if err := json.Unmarshal(response, &prune); err != nil {
+		return 0, err
+	}
+
+	return prune.Pruned, nil
+}
+
+// CreateChannel creates a new channel in the given server
+func (c *Client) CreateChannel(server Server, params ChannelParams, opts ...RequestOption) (Channel, error) {
+	var channel Channel
+
+	if err := params.Validate(); err != nil {
+		return channel, err
+	}
+	if err := c.checkServerPermissions(server.ID, PermissionManageChannels); err != nil {
+		return channel, err
+	}
+
+	response, err := c.request(
+		"POST",
+		fmt.Sprintf("%s/%s/channels", apiServers, server.ID),
+		params,
+		opts...,
+	)
+	if err != nil {
+		return channel, err
+	}
+
+	if err := json.Unmarshal(response, &channel); err != nil {
+		return channel, err
+	}
+	channel.ServerID = server.ID
+
+	// Unless CHANNEL_CREATE came first, it replaces this copy when it arrives
+	c.serversLock.Lock()
+	if c.getChannelIndex(server.ID, channel.ID) < 0 {
+		if tmp, ok := c.Servers[server.ID]; ok {
+			tmp.Channels = append(tmp.Channels, channel)
+			c.Servers[server.ID] = tmp
+		}
+	}
+	c.serversLock.Unlock()
+
+	return channel, nil
+}
+
+// FetchChannel requests the channel with the given ID from Discord,
+// GetChannelByID only looks into the cache
+func (c *Client) FetchChannel(channelID string) (Channel, error) {
+	var channel Channel
+
+	response, err := c.get(fmt.Sprintf("%s/%s", apiChannels, channelID))
+	if err != nil {
+		return channel, err
+	}
+
+	if err := json.Unmarshal(response, &channel); err != nil {
+		return channel, err
+	}
+
+	return channel, nil
+}
+
+// DeleteChannel deletes the channel
+func (c *Client) DeleteChannel(channelID string, opts ...RequestOption) (Channel, error) {
+	var channel Channel
+
+	if err := c.checkChannelPermissions(channelID, PermissionManageChannels); err != nil {
+		return channel, err
+	}
+
+	response, err := c.request(
+		"DELETE",
+		fmt.Sprintf("%s/%s", apiChannels, channelID),
+		nil,
+		opts...,
+	)
+	if err != nil {
+		return channel, err
+	}
+
+	if err := json.Unmarshal(response, &channel); err != nil {
+		return channel, err
+	}
+
+	c.uncacheChannel(channel.ServerID, channel.ID)
+
+	return channel, nil
+}
+
+// ReorderChannels sets the position of each channel from the order of the
+// given IDs, channels of different kinds are sorted separately by Discord
+func (c *Client) ReorderChannels(serverID string, channelIDs []string, opts ...RequestOption) error {
+	if err := c.checkServerPermissions(serverID, PermissionManageChannels); err != nil {
+		return err
+	}
+
+	positions := make([]map[string]interface{}, len(channelIDs))
+	for i, id := range channelIDs {
+		positions[i] = map[string]interface{}{
+			"id":       id,
+			"position": i,
+		}
+	}
+
+	_, err := c.request(
+		"PATCH",
+		fmt.Sprintf("%s/%s/channels", apiServers, serverID),
+		positions,
+		opts...,
+	)
+	if err != nil {
+		return err
+	}
+
+	c.serversLock.Lock()
+	if tmp, ok := c.Servers[serverID]; ok {
+		for i, id := range channelIDs {
+			if j := c.getChannelIndex(serverID, id); j >= 0 {
+				tmp.Channels[j].Position = i
+			}
+		}
+	}
+	c.serversLock.Unlock()
+
+	return nil
 }
 
 // EditChannel edits a channel with the given parameters
 // among (name string, topic string, position int)
-func (c *Client) EditChannel(channel Channel, params map[string]interface{}) error {
+func (c *Client) EditChannel(channel Channel, params map[string]interface{}, opts ...RequestOption) error {
 	_, err := c.request(
 		"PATCH",
 		fmt.Sprintf("%s/%s", apiChannels, channel.ID),
 		params,
+		opts...,
 	)
 	return err
 }
 
+// SetChannelPermission creates or replaces the permission overwrite of a role
+// or member (targetType is OverwriteRole or OverwriteMember) in the channel
+func (c *Client) SetChannelPermission(channelID string, targetID string, targetType string, allow Permissions, deny Permissions, opts ...RequestOption) error {
+	_, err := c.request(
+		"PUT",
+		fmt.Sprintf("%s/%s/permissions/%s", apiChannels, channelID, targetID),
+		PermissionOverwrite{
+			ID:    targetID,
+			Type:  targetType,
+			Allow: allow,
+			Deny:  deny,
+		},
+		opts...,
+	)
+	return err
+}
+
+// DeleteChannelPermission removes the permission overwrite of a role or
+// member from the channel
+func (c *Client) DeleteChannelPermission(channelID string, targetID string, opts ...RequestOption) error {
+	_, err := c.request(
+		"DELETE",
+		fmt.Sprintf("%s/%s/permissions/%s", apiChannels, channelID, targetID),
+		nil,
+		opts...,
+	)
+	return err
+}
+
+// LockChannel prevents @everyone from sending messages in the channel,
+// other bits of the existing overwrite are kept
+func (c *Client) LockChannel(channelID string, opts ...RequestOption) error {
+	channel := c.GetChannelByID(channelID)
+	if channel.ID == "" {
+		return fmt.Errorf("channel %s not found", channelID)
+	}
+	overwrite := channel.everyoneOverwrite()
+
+	return c.SetChannelPermission(
+		channel.ID,
+		channel.ServerID,
+		OverwriteRole,
+		overwrite.Allow&^PermissionSendMessages,
+		overwrite.Deny|PermissionSendMessages,
+		opts...,
+	)
+}
+
+// UnlockChannel lets @everyone send messages in the channel again
+func (c *Client) UnlockChannel(channelID string, opts ...RequestOption) error {
+	channel := c.GetChannelByID(channelID)
+	if channel.ID == "" {
+		return fmt.Errorf("channel %s not found", channelID)
+	}
+	overwrite := channel.everyoneOverwrite()
+
+	deny := overwrite.Deny &^ PermissionSendMessages
+	if overwrite.Allow == 0 && deny == 0 {
+		return c.DeleteChannelPermission(channel.ID, channel.ServerID, opts...)
+	}
+	return c.SetChannelPermission(channel.ID, channel.ServerID, OverwriteRole, overwrite.Allow, deny, opts...)
+}
+
 // GetRegion returns the Region object corresponding to the given server
 func (c *Client) GetRegion(server Server) (Region, error) {
 	var region Region
@@ -943,6 +2739,8 @@ func (c *Client) Run() {
 // Stop closes the WebSocket connection
 func (c *Client) Stop() {
 	log.Print("Closing connection")
-	c.keepaliveTicker.Stop()
+	if c.keepaliveTicker != nil {
+		c.keepaliveTicker.Stop()
+	}
 	c.wsConn.Close()
 }
@@ -0,0 +1,40 @@
+package discord
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestRedactURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{
+			apiWebhooks + "/123/secret?wait=true",
+			apiWebhooks + "/123/REDACTED?wait=true",
+		},
+		{
+			apiWebhooks + "/123/secret/slack",
+			apiWebhooks + "/123/REDACTED/slack",
+		},
+		{
+			apiWebhooks + "/123",
+			apiWebhooks + "/123",
+		},
+		{
+			apiChannels + "/123/messages",
+			apiChannels + "/123/messages",
+		},
+	}
+
+	for _, test := range tests {
+		u, err := url.Parse(test.url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := redactURL(u); got != test.want {
+			t.Errorf("redactURL(%s) = %s, want %s", test.url, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package discord
+
+import (
+	"fmt"
+	"time"
+)
+
+// Limits enforced by Discord on embeds
+const (
+	EmbedTitleLimit       = 256
+	EmbedDescriptionLimit = 2048
+	EmbedFieldCountLimit  = 25
+	EmbedFieldNameLimit   = 256
+	EmbedFieldValueLimit  = 1024
+	EmbedFooterTextLimit  = 2048
+	EmbedAuthorNameLimit  = 256
+	EmbedTotalLimit       = 6000
+)
+
+// Embed is the rich content displayed under a message
+type Embed struct {
+	Title       string          `json:"title,omitempty"`
+	Type        string          `json:"type,omitempty"`
+	Description string          `json:"description,omitempty"`
+	URL         string          `json:"url,omitempty"`
+	Timestamp   string          `json:"timestamp,omitempty"`
+	Color       int             `json:"color,omitempty"`
+	Footer      *EmbedFooter    `json:"footer,omitempty"`
+	Image       *EmbedImage     `json:"image,omitempty"`
+	Thumbnail   *EmbedThumbnail `json:"thumbnail,omitempty"`
+	Video       *EmbedVideo     `json:"video,omitempty"`
+	Provider    *EmbedProvider  `json:"provider,omitempty"`
+	Author      *EmbedAuthor    `json:"author,omitempty"`
+	Fields      []EmbedField    `json:"fields,omitempty"`
+}
+
+// EmbedFooter is the small text at the bottom of an embed
+type EmbedFooter struct {
+	Text         string `json:"text"`
+	IconURL      string `json:"icon_url,omitempty"`
+	ProxyIconURL string `json:"proxy_icon_url,omitempty"`
+}
+
+// EmbedImage is the large image of an embed
+type EmbedImage struct {
+	URL      string `json:"url,omitempty"`
+	ProxyURL string `json:"proxy_url,omitempty"`
+	Width    int    `json:"width,omitempty"`
+	Height   int    `json:"height,omitempty"`
+}
+
+// EmbedThumbnail is the small image on the side of an embed
+type EmbedThumbnail struct {
+	URL      string `json:"url,omitempty"`
+	ProxyURL string `json:"proxy_url,omitempty"`
+	Width    int    `json:"width,omitempty"`
+	Height   int    `json:"height,omitempty"`
+}
+
+// EmbedVideo is set by Discord on embeds of video links
+type EmbedVideo struct {
+	URL    string `json:"url,omitempty"`
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+}
+
+// EmbedProvider is the website an embed comes from
+type EmbedProvider struct {
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+// EmbedAuthor is displayed at the top of an embed
+type EmbedAuthor struct {
+	Name         string `json:"name,omitempty"`
+	URL          string `json:"url,omitempty"`
+	IconURL      string `json:"icon_url,omitempty"`
+	ProxyIconURL string `json:"proxy_icon_url,omitempty"`
+}
+
+// EmbedField is a name/value pair displayed in the body of an embed
+type EmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+// Validate checks the embed against Discord's length limits
+func (e *Embed) Validate() error {
+	total := 0
+	check := func(what string, value string, limit int) error {
+		length := len([]rune(value))
+		total += length
+		if length > limit {
+			return fmt.Errorf("embed %s is %d characters long, limit is %d", what, length, limit)
+		}
+		return nil
+	}
+
+	if err := check("title", e.Title, EmbedTitleLimit); err != nil {
+		return err
+	}
+	if err := check("description", e.Description, EmbedDescriptionLimit); err != nil {
+		return err
+	}
+	if e.Footer != nil {
+		if err := check("footer text", e.Footer.Text, EmbedFooterTextLimit); err != nil {
+			return err
+		}
+	}
+	if e.Author != nil {
+		if err := check("author name", e.Author.Name, EmbedAuthorNameLimit); err != nil {
+			return err
+		}
+	}
+
+	if len(e.Fields) > EmbedFieldCountLimit {
+		return fmt.Errorf("embed has %d fields, limit is %d", len(e.Fields), EmbedFieldCountLimit)
+	}
+	for i, field := range e.Fields {
+		if field.Name == "" || field.Value == "" {
+			return fmt.Errorf("embed field %d must have a name and a value", i)
+		}
+		if err := check(fmt.Sprintf("field %d name", i), field.Name, EmbedFieldNameLimit); err != nil {
+			return err
+		}
+		if err := check(fmt.Sprintf("field %d value", i), field.Value, EmbedFieldValueLimit); err != nil {
+			return err
+		}
+	}
+
+	if total > EmbedTotalLimit {
+		return fmt.Errorf("embed is %d characters long, limit is %d", total, EmbedTotalLimit)
+	}
+	return nil
+}
+
+// EmbedBuilder helps creating an embed, calls can be chained:
+//
+//	embed, err := discord.NewEmbed().
+//		SetTitle("Build #42").
+//		SetColor(0x00ff00).
+//		AddField("Status", "passed", true).
+//		Build()
+type EmbedBuilder struct {
+	embed Embed
+}
+
+// NewEmbed starts a new embed
+func NewEmbed() *EmbedBuilder {
+	return &EmbedBuilder{embed: Embed{Type: "rich"}}
+}
+
+// SetTitle sets the title of the embed
+func (b *EmbedBuilder) SetTitle(title string) *EmbedBuilder {
+	b.embed.Title = title
+	return b
+}
+
+// SetDescription sets the main text of the embed
+func (b *EmbedBuilder) SetDescription(description string) *EmbedBuilder {
+	b.embed.Description = description
+	return b
+}
+
+// SetURL makes the title a link to url
+func (b *EmbedBuilder) SetURL(url string) *EmbedBuilder {
+	b.embed.URL = url
+	return b
+}
+
+// SetColor sets the color of the embed's left border, as 0xRRGGBB
+func (b *EmbedBuilder) SetColor(color int) *EmbedBuilder {
+	b.embed.Color = color
+	return b
+}
+
+// SetTimestamp sets the time displayed next to the footer
+func (b *EmbedBuilder) SetTimestamp(t time.Time) *EmbedBuilder {
+	b.embed.Timestamp = t.UTC().Format(time.RFC3339)
+	return b
+}
+
+// SetFooter sets the footer text and icon
+func (b *EmbedBuilder) SetFooter(text string, iconURL string) *EmbedBuilder {
+	b.embed.Footer = &EmbedFooter{Text: text, IconURL: iconURL}
+	return b
+}
+
+// SetImage sets the large image of the embed
+func (b *EmbedBuilder) SetImage(url string) *EmbedBuilder {
+	b.embed.Image = &EmbedImage{URL: url}
+	return b
+}
+
+// SetThumbnail sets the small image on the side of the embed
+func (b *EmbedBuilder) SetThumbnail(url string) *EmbedBuilder {
+	b.embed.Thumbnail = &EmbedThumbnail{URL: url}
+	return b
+}
+
+// SetAuthor sets the author displayed at the top of the embed
+func (b *EmbedBuilder) SetAuthor(name string, url string, iconURL string) *EmbedBuilder {
+	b.embed.Author = &EmbedAuthor{Name: name, URL: url, IconURL: iconURL}
+	return b
+}
+
+// AddField appends a field, inline fields are displayed side by side
+func (b *EmbedBuilder) AddField(name string, value string, inline bool) *EmbedBuilder {
+	b.embed.Fields = append(b.embed.Fields, EmbedField{Name: name, Value: value, Inline: inline})
+	return b
+}
+
+// Build validates and returns the embed
+func (b *EmbedBuilder) Build() (*Embed, error) {
+	embed := b.embed
+	embed.Fields = append([]EmbedField(nil), b.embed.Fields...)
+	if err := embed.Validate(); err != nil {
+		return nil, err
+	}
+	return &embed, nil
+}
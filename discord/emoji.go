@@ -0,0 +1,193 @@
+package discord
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// Emoji is either a unicode emoji (only Name is set) or a custom emoji
+// of a server
+type Emoji struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Roles allowed to use the emoji, everyone if empty
+	Roles         []string `json:"roles,omitempty"`
+	RequireColons bool     `json:"require_colons,omitempty"`
+	Managed       bool     `json:"managed,omitempty"`
+	Animated      bool     `json:"animated,omitempty"`
+}
+
+// MessageFormat returns the emoji the way it must be written in a message
+// to be displayed, such as <:name:id>
+func (e *Emoji) MessageFormat() string {
+	if e.ID == "" {
+		return e.Name
+	}
+	if e.Animated {
+		return fmt.Sprintf("<a:%s:%s>", e.Name, e.ID)
+	}
+	return fmt.Sprintf("<:%s:%s>", e.Name, e.ID)
+}
+
+// URL returns the image of a custom emoji
+func (e *Emoji) URL() string {
+	if e.ID == "" {
+		return ""
+	}
+	if e.Animated {
+		return fmt.Sprintf("https://cdn.discordapp.com/emojis/%s.gif", e.ID)
+	}
+	return fmt.Sprintf("https://cdn.discordapp.com/emojis/%s.png", e.ID)
+}
+
+// APIName returns the emoji the way reaction endpoints expect it
+func (e *Emoji) APIName() string {
+	if e.ID == "" {
+		return e.Name
+	}
+	return fmt.Sprintf("%s:%s", e.Name, e.ID)
+}
+
+// Equals tells whether both emojis are the same
+func (e *Emoji) Equals(other Emoji) bool {
+	if e.ID != "" || other.ID != "" {
+		return e.ID == other.ID
+	}
+	return e.Name == other.Name
+}
+
+// escapeEmoji makes an emoji (unicode or "name:id") usable in an URL path
+func escapeEmoji(emoji string) string {
+	return url.PathEscape(emoji)
+}
+
+// ServerEmojisUpdate is received when the custom emojis of a server change
+type ServerEmojisUpdate struct {
+	ServerID string  `json:"guild_id"`
+	Emojis   []Emoji `json:"emojis"`
+}
+
+type serverEmojisUpdateEvent struct {
+	OpCode int                `json:"op"`
+	Type   string             `json:"t"`
+	Data   ServerEmojisUpdate `json:"d"`
+}
+
+// CreateEmoji adds a custom emoji to the server from a PNG, JPEG or GIF
+// image, roles may be nil to let everyone use it
+func (c *Client) CreateEmoji(serverID string, name string, image []byte, roles []string, opts ...RequestOption) (Emoji, error) {
+	var emoji Emoji
+
+	if err := c.checkServerPermissions(serverID, PermissionManageEmojis); err != nil {
+		return emoji, err
+	}
+
+	m := map[string]interface{}{
+		"name":  name,
+		"image": imageDataURI(image),
+	}
+	if roles != nil {
+		m["roles"] = roles
+	}
+
+	response, err := c.request(
+		"POST",
+		fmt.Sprintf("%s/%s/emojis", apiServers, serverID),
+		m,
+		opts...,
+	)
+	if err != nil {
+		return emoji, err
+	}
+
+	if err := json.Unmarshal(response, &emoji); err != nil {
+		return emoji, err
+	}
+
+	// https://github.com/golang/go/issues/3117
+	c.serversLock.Lock()
+	if tmp, ok := c.Servers[serverID]; ok {
+		tmp.Emojis = append(tmp.Emojis, emoji)
+		c.Servers[serverID] = tmp
+	}
+	c.serversLock.Unlock()
+
+	return emoji, nil
+}
+
+// EditEmoji renames the custom emoji and sets the roles allowed to use it,
+// roles may be nil to leave them untouched
+func (c *Client) EditEmoji(serverID string, emojiID string, name string, roles []string, opts ...RequestOption) (Emoji, error) {
+	var emoji Emoji
+
+	if err := c.checkServerPermissions(serverID, PermissionManageEmojis); err != nil {
+		return emoji, err
+	}
+
+	m := map[string]interface{}{
+		"name": name,
+	}
+	if roles != nil {
+		m["roles"] = roles
+	}
+
+	response, err := c.request(
+		"PATCH",
+		fmt.Sprintf("%s/%s/emojis/%s", apiServers, serverID, emojiID),
+		m,
+		opts...,
+	)
+	if err != nil {
+		return emoji, err
+	}
+
+	if err := json.Unmarshal(response, &emoji); err != nil {
+		return emoji, err
+	}
+
+	c.serversLock.Lock()
+	if tmp, ok := c.Servers[serverID]; ok {
+		for i := range tmp.Emojis {
+			if tmp.Emojis[i].ID == emoji.ID {
+				tmp.Emojis[i] = emoji
+				break
+			}
+		}
+	}
+	c.serversLock.Unlock()
+
+	return emoji, nil
+}
+
+// DeleteEmoji removes the custom emoji from the server
+func (c *Client) DeleteEmoji(serverID string, emojiID string, opts ...RequestOption) error {
+	if err := c.checkServerPermissions(serverID, PermissionManageEmojis); err != nil {
+		return err
+	}
+
+	_, err := c.request(
+		"DELETE",
+		fmt.Sprintf("%s/%s/emojis/%s", apiServers, serverID, emojiID),
+		nil,
+		opts...,
+	)
+	if err != nil {
+		return err
+	}
+
+	// https://github.com/golang/go/issues/3117
+	c.serversLock.Lock()
+	if tmp, ok := c.Servers[serverID]; ok {
+		for i := range tmp.Emojis {
+			if tmp.Emojis[i].ID == emojiID {
+				tmp.Emojis = append(tmp.Emojis[:i], tmp.Emojis[i+1:]...)
+				break
+			}
+		}
+		c.Servers[serverID] = tmp
+	}
+	c.serversLock.Unlock()
+
+	return nil
+}
@@ -0,0 +1,181 @@
+package discord
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// What happens to subscribers whose subscription expired
+const (
+	IntegrationExpireRemoveRole = 0
+	IntegrationExpireKick       = 1
+)
+
+// Integration links a server to a Twitch or YouTube account, giving a role
+// to its subscribers
+type Integration struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Type    string `json:"type"` // "twitch" or "youtube"
+	Enabled bool   `json:"enabled"`
+	Syncing bool   `json:"syncing"`
+	// Role given to subscribers
+	RoleID            string             `json:"role_id"`
+	ExpireBehavior    int                `json:"expire_behavior"`
+	ExpireGracePeriod int                `json:"expire_grace_period"` // In days
+	EnableEmoticons   bool               `json:"enable_emoticons"`
+	User              User               `json:"user"`
+	Account           IntegrationAccount `json:"account"`
+	SyncedAt          string             `json:"synced_at"`
+}
+
+// IntegrationAccount is the Twitch or YouTube account of an integration
+type IntegrationAccount struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// IntegrationParams holds the settings of an integration
+type IntegrationParams struct {
+	ExpireBehavior    int  `json:"expire_behavior"`
+	ExpireGracePeriod int  `json:"expire_grace_period"`
+	EnableEmoticons   bool `json:"enable_emoticons"`
+}
+
+// ServerEmbed holds the settings of the server's embed widget
+type ServerEmbed struct {
+	Enabled bool `json:"enabled"`
+	// Channel the widget invites to
+	ChannelID string `json:"channel_id"`
+}
+
+// ServerIntegrations returns the integrations of the server
+func (c *Client) ServerIntegrations(serverID string) ([]Integration, error) {
+	var integrations []Integration
+
+	if err := c.checkServerPermissions(serverID, PermissionManageServer); err != nil {
+		return integrations, err
+	}
+
+	response, err := c.get(fmt.Sprintf("%s/%s/integrations", apiServers, serverID))
+	if err != nil {
+		return integrations, err
+	}
+
+	if err := json.Unmarshal(response, &integrations); err != nil {
+		return integrations, err
+	}
+
+	return integrations, nil
+}
+
+// CreateIntegration attaches the integration of the given type ("twitch" or
+// "youtube") and ID, taken from the user's connections, to the server
+func (c *Client) CreateIntegration(serverID string, integrationType string, integrationID string, opts ...RequestOption) error {
+	if err := c.checkServerPermissions(serverID, PermissionManageServer); err != nil {
+		return err
+	}
+
+	_, err := c.request(
+		"POST",
+		fmt.Sprintf("%s/%s/integrations", apiServers, serverID),
+		map[string]string{
+			"type": integrationType,
+			"id":   integrationID,
+		},
+		opts...,
+	)
+	return err
+}
+
+// EditIntegration changes the expire behaviour, grace period and emoticons
+// settings of the integration
+func (c *Client) EditIntegration(serverID string, integrationID string, params IntegrationParams, opts ...RequestOption) error {
+	if err := c.checkServerPermissions(serverID, PermissionManageServer); err != nil {
+		return err
+	}
+
+	_, err := c.request(
+		"PATCH",
+		fmt.Sprintf("%s/%s/integrations/%s", apiServers, serverID, integrationID),
+		params,
+		opts...,
+	)
+	return err
+}
+
+// DeleteIntegration detaches the integration from the server
+func (c *Client) DeleteIntegration(serverID string, integrationID string, opts ...RequestOption) error {
+	if err := c.checkServerPermissions(serverID, PermissionManageServer); err != nil {
+		return err
+	}
+
+	_, err := c.request(
+		"DELETE",
+		fmt.Sprintf("%s/%s/integrations/%s", apiServers, serverID, integrationID),
+		nil,
+		opts...,
+	)
+	return err
+}
+
+// SyncIntegration updates the subscribers of the integration
+func (c *Client) SyncIntegration(serverID string, integrationID string, opts ...RequestOption) error {
+	if err := c.checkServerPermissions(serverID, PermissionManageServer); err != nil {
+		return err
+	}
+
+	_, err := c.request(
+		"POST",
+		fmt.Sprintf("%s/%s/integrations/%s/sync", apiServers, serverID, integrationID),
+		nil,
+		opts...,
+	)
+	return err
+}
+
+// ServerEmbed returns the settings of the server's embed widget
+func (c *Client) ServerEmbed(serverID string) (ServerEmbed, error) {
+	var embed ServerEmbed
+
+	if err := c.checkServerPermissions(serverID, PermissionManageServer); err != nil {
+		return embed, err
+	}
+
+	response, err := c.get(fmt.Sprintf("%s/%s/embed", apiServers, serverID))
+	if err != nil {
+		return embed, err
+	}
+
+	if err := json.Unmarshal(response, &embed); err != nil {
+		return embed, err
+	}
+
+	return embed, nil
+}
+
+// EditServerEmbed enables or disables the server's embed widget and sets
+// the channel it invites to
+func (c *Client) EditServerEmbed(serverID string, embed ServerEmbed, opts ...RequestOption) (ServerEmbed, error) {
+	var edited ServerEmbed
+
+	if err := c.checkServerPermissions(serverID, PermissionManageServer); err != nil {
+		return edited, err
+	}
+
+	response, err := c.request(
+		"PATCH",
+		fmt.Sprintf("%s/%s/embed", apiServers, serverID),
+		embed,
+		opts...,
+	)
+	if err != nil {
+		return edited, err
+	}
+
+	if err := json.Unmarshal(response, &edited); err != nil {
+		return edited, err
+	}
+
+	return edited, nil
+}
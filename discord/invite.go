@@ -0,0 +1,172 @@
+package discord
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Invite lets users join a server through one of its channels
+type Invite struct {
+	Code string `json:"code"`
+	// Only ID, Name, Icon and Splash are set
+	Server Server `json:"guild"`
+	// Only ID, Name and Type are set
+	Channel Channel `json:"channel"`
+
+	// Only known by members who can manage the server or channel
+	Inviter   User   `json:"inviter"`
+	Uses      int    `json:"uses"`
+	MaxUses   int    `json:"max_uses"`
+	MaxAge    int    `json:"max_age"` // In seconds, 0 never expires
+	Temporary bool   `json:"temporary"`
+	CreatedAt string `json:"created_at"`
+	Revoked   bool   `json:"revoked"`
+}
+
+// ExpiresAt returns when the invite stops working, the zero time if it
+// never expires or its creation date is unknown
+func (invite *Invite) ExpiresAt() time.Time {
+	if invite.MaxAge == 0 {
+		return time.Time{}
+	}
+	created, err := time.Parse(time.RFC3339, invite.CreatedAt)
+	if err != nil {
+		return time.Time{}
+	}
+	return created.Add(time.Duration(invite.MaxAge) * time.Second)
+}
+
+// URL returns the link to share the invite
+func (invite *Invite) URL() string {
+	return "https://discord.gg/" + invite.Code
+}
+
+// ParseInviteCode returns the code of an invite given either as a code or
+// as a full URL such as https://discord.gg/code or
+// https://discordapp.com/invite/code
+func ParseInviteCode(invite string) string {
+	code := strings.TrimSpace(invite)
+	for _, prefix := range []string{"https://", "http://"} {
+		code = strings.TrimPrefix(code, prefix)
+	}
+	for _, prefix := range []string{"www.", "discord.gg/", "discordapp.com/invite/", "discord.com/invite/"} {
+		code = strings.TrimPrefix(code, prefix)
+	}
+	// Drop query strings and trailing slashes
+	if i := strings.IndexAny(code, "?#/"); i >= 0 {
+		code = code[:i]
+	}
+	return code
+}
+
+// CreateInvite creates an invite to the channel. maxAge is in seconds and
+// 0 never expires, maxUses 0 is unlimited, temporary members are kicked
+// when they disconnect unless given a role, and unique forces a new code
+// instead of reusing a similar one.
+func (c *Client) CreateInvite(channelID string, maxAge int, maxUses int, temporary bool, unique bool, opts ...RequestOption) (Invite, error) {
+	var invite Invite
+
+	if err := c.checkChannelPermissions(channelID, PermissionCreateInstantInvite); err != nil {
+		return invite, err
+	}
+
+	response, err := c.request(
+		"POST",
+		fmt.Sprintf("%s/%s/invites", apiChannels, channelID),
+		map[string]interface{}{
+			"max_age":   maxAge,
+			"max_uses":  maxUses,
+			"temporary": temporary,
+			"unique":    unique,
+		},
+		opts...,
+	)
+	if err != nil {
+		return invite, err
+	}
+
+	if err := json.Unmarshal(response, &invite); err != nil {
+		return invite, err
+	}
+
+	return invite, nil
+}
+
+// Invite returns the invite with the given code or URL without joining
+func (c *Client) Invite(code string) (Invite, error) {
+	var invite Invite
+
+	response, err := c.get(fmt.Sprintf("%s/%s", apiInvite, ParseInviteCode(code)))
+	if err != nil {
+		return invite, err
+	}
+
+	if err := json.Unmarshal(response, &invite); err != nil {
+		return invite, err
+	}
+
+	return invite, nil
+}
+
+// ServerInvites returns the invites of every channel of the server
+func (c *Client) ServerInvites(serverID string) ([]Invite, error) {
+	var invites []Invite
+
+	if err := c.checkServerPermissions(serverID, PermissionManageServer); err != nil {
+		return invites, err
+	}
+
+	response, err := c.get(fmt.Sprintf("%s/%s/invites", apiServers, serverID))
+	if err != nil {
+		return invites, err
+	}
+
+	if err := json.Unmarshal(response, &invites); err != nil {
+		return invites, err
+	}
+
+	return invites, nil
+}
+
+// ChannelInvites returns the invites of the channel
+func (c *Client) ChannelInvites(channelID string) ([]Invite, error) {
+	var invites []Invite
+
+	if err := c.checkChannelPermissions(channelID, PermissionManageChannels); err != nil {
+		return invites, err
+	}
+
+	response, err := c.get(fmt.Sprintf("%s/%s/invites", apiChannels, channelID))
+	if err != nil {
+		return invites, err
+	}
+
+	if err := json.Unmarshal(response, &invites); err != nil {
+		return invites, err
+	}
+
+	return invites, nil
+}
+
+// DeleteInvite revokes the invite with the given code or URL
+func (c *Client) DeleteInvite(code string, opts ...RequestOption) (Invite, error) {
+	var invite Invite
+
+	response, err := c.request(
+		"DELETE",
+		fmt.Sprintf("%s/%s", apiInvite, ParseInviteCode(code)),
+		nil,
+		opts...,
+	)
+	if err != nil {
+		return invite, err
+	}
+
+	if err := json.Unmarshal(response, &invite); err != nil {
+		return invite, err
+	}
+
+	return invite, nil
+}
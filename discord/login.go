@@ -1,9 +1,53 @@
 package discord
 
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+)
+
 type tokenStruct struct {
-    Value string `json:"token"`
+	Value string `json:"token"`
 }
 
 type gatewayStruct struct {
-    Value string `json:"url"`
+	Value string `json:"url"`
+}
+
+// loadToken reads a previously saved token from the given file
+func loadToken(filename string) (string, error) {
+	dump, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(dump)), nil
+}
+
+// saveToken writes the token to the given file, readable only by its owner
+func saveToken(filename string, token string) error {
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// An already existing file keeps its permissions, restrict them before
+	// writing the token
+	if err := f.Chmod(0600); err != nil {
+		return err
+	}
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := f.WriteString(token); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// isUnauthorized tells whether the error is Discord rejecting the token
+func isUnauthorized(err error) bool {
+	apiErr, ok := err.(*APIError)
+	return ok && apiErr.StatusCode == http.StatusUnauthorized
 }
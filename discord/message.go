@@ -1,5 +1,26 @@
 package discord
 
+import (
+	"context"
+	"io"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// BulkDeleteMaxAge is the age past which messages can't be bulk deleted
+const BulkDeleteMaxAge = 14 * 24 * time.Hour
+
+// Purge leaves this margin before BulkDeleteMaxAge, as messages keep ageing
+// while the history is walked
+const bulkDeleteMargin = time.Hour
+
+// Maximum number of messages per bulk delete request
+const bulkDeleteLimit = 100
+
+// Discord epoch of snowflake IDs, in milliseconds
+const discordEpoch = 1420070400000
+
 // Message if the structure for a received message
 type Message struct {
 	EditedTimestamp string `json:"edited_timestamp"`
@@ -7,19 +28,48 @@ type Message struct {
 	TTS             bool   `json:"tts"`
 	Content         string `json:"content"`
 	MentionEveryone bool   `json:"mention_everyone"`
+	Pinned          bool   `json:"pinned"`
 	ID              string `json:"id"`
 	ChannelID       string `json:"channel_id"`
 	Author          User   `json:"author"`
 	Mentions        []User `json:"mentions"`
 
-	// TODO: Don't know how these are typed
-	Attachments interface{} `json:"attachments"`
-	Embeds      interface{} `json:"embeds"`
+	Attachments []Attachment `json:"attachments"`
+	Embeds      []Embed      `json:"embeds"`
+	Reactions   []Reaction   `json:"reactions"`
+}
+
+// Attachment is a file attached to a message
+type Attachment struct {
+	ID       string `json:"id"`
+	Filename string `json:"filename"`
+	Size     int    `json:"size"`
+	URL      string `json:"url"`
+	ProxyURL string `json:"proxy_url"`
+	// Only set for images
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// MessageParams holds everything that can be sent in a message, Files
+// are ignored when editing
+type MessageParams struct {
+	Content string `json:"content,omitempty"`
+	TTS     bool   `json:"tts,omitempty"`
+	Embed   *Embed `json:"embed,omitempty"`
+	Files   []File `json:"-"`
+}
+
+// File is uploaded along with a message, Reader is read until EOF
+type File struct {
+	Name        string
+	ContentType string
+	Reader      io.Reader
 }
 
 // GetServer returns the server in which the message has been sent
 func (message *Message) GetServer(client *Client) Server {
-	return client.Servers[message.GetChannel(client).ServerID]
+	return client.GetServerByID(message.GetChannel(client).ServerID)
 }
 
 // GetChannel returns the channel in which the message has been sent
@@ -27,12 +77,91 @@ func (message *Message) GetChannel(client *Client) Channel {
 	return client.GetChannelByID(message.ChannelID)
 }
 
+// Time returns the creation time of the message, read from its ID
+func (message *Message) Time() time.Time {
+	return snowflakeTime(message.ID)
+}
+
+// snowflakeTime returns the time at which the ID was generated
+func snowflakeTime(id string) time.Time {
+	n, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	ms := int64(n>>22) + discordEpoch
+	return time.Unix(ms/1000, (ms%1000)*int64(time.Millisecond))
+}
+
+// GetChannelID returns the ID of the channel the message was sent in, so
+// that a message can be replied to with client.SendMessage(message, ...)
+func (message Message) GetChannelID() string {
+	return message.ChannelID
+}
+
+// AddReaction reacts to the message with the given emoji
+func (message *Message) AddReaction(client *Client, emoji string) error {
+	return client.AddReaction(*message, message.ID, emoji)
+}
+
+// Pin pins the message in its channel
+func (message *Message) Pin(client *Client) error {
+	return client.PinMessage(*message, message.ID)
+}
+
+// Unpin unpins the message from its channel
+func (message *Message) Unpin(client *Client) error {
+	return client.UnpinMessage(*message, message.ID)
+}
+
 type messageEvent struct {
 	OpCode int     `json:"op"`
 	Type   string  `json:"t"`
 	Data   Message `json:"d"`
 }
 
+// MessageDeleteBulk is received when several messages are deleted at once
+type MessageDeleteBulk struct {
+	IDs       []string `json:"ids"`
+	ChannelID string   `json:"channel_id"`
+}
+
+type messageDeleteBulkEvent struct {
+	OpCode int               `json:"op"`
+	Type   string            `json:"t"`
+	Data   MessageDeleteBulk `json:"d"`
+}
+
+// PurgeFilter selects the messages deleted by Purge, empty fields match
+// every message
+type PurgeFilter struct {
+	AuthorID string
+	Content  *regexp.Regexp
+	// Only messages older than OlderThan and younger than NewerThan
+	OlderThan time.Duration
+	NewerThan time.Duration
+}
+
+// Match tells whether the message should be purged
+func (filter *PurgeFilter) Match(message Message) bool {
+	if filter.AuthorID != "" && message.Author.ID != filter.AuthorID {
+		return false
+	}
+	if filter.Content != nil && !filter.Content.MatchString(message.Content) {
+		return false
+	}
+	age := time.Since(message.Time())
+	if filter.OlderThan > 0 && age < filter.OlderThan {
+		return false
+	}
+	if filter.NewerThan > 0 && age > filter.NewerThan {
+		return false
+	}
+	return true
+}
+
+// The typing indicator lasts 10 seconds, refresh it a bit before
+const typingInterval = 8 * time.Second
+
 // Typing is the structure received when someone starts typing a message
 type Typing struct {
 	UserID    string `json:"user_id"`
@@ -45,3 +174,74 @@ type typingEvent struct {
 	Type   string `json:"t"`
 	Data   Typing `json:"d"`
 }
+
+// Maximum number of messages Discord returns per history request
+const messagesPageSize = 100
+
+// MessageIterator walks a channel's history page by page, use it like
+// a bufio.Scanner:
+//
+//	it := client.ChannelHistory(channel)
+//	for it.Next(ctx) {
+//		message := it.Message()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type MessageIterator struct {
+	client    *Client
+	channelID string
+	before    string
+	page      []Message
+	current   Message
+	err       error
+	exhausted bool
+}
+
+// Next advances to the next (older) message, fetching a new page when
+// needed. It returns false once the history is exhausted, an error
+// occurred or ctx is done.
+func (it *MessageIterator) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+	if err := ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+
+	if len(it.page) == 0 {
+		if it.exhausted {
+			return false
+		}
+
+		page, err := it.client.channelMessages(ctx, it.channelID, it.before, "", "", messagesPageSize)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		// A short page means there is nothing older
+		if len(page) < messagesPageSize {
+			it.exhausted = true
+		}
+		if len(page) == 0 {
+			return false
+		}
+		it.page = page
+		it.before = page[len(page)-1].ID
+	}
+
+	it.current = it.page[0]
+	it.page = it.page[1:]
+	return true
+}
+
+// Message returns the message reached by the last call to Next
+func (it *MessageIterator) Message() Message {
+	return it.current
+}
+
+// Err returns the error which stopped the iteration, if any
+func (it *MessageIterator) Err() error {
+	return it.err
+}
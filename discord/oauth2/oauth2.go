@@ -0,0 +1,194 @@
+// Package oauth2 lets users grant access to their Discord account through
+// the OAuth2 authorization code or client credentials flows, instead of
+// handing over their password.
+package oauth2
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gdraynz/go-discord/discord"
+)
+
+const (
+	AuthURL  = "https://discordapp.com/api/oauth2/authorize"
+	TokenURL = "https://discordapp.com/api/oauth2/token"
+)
+
+// Scopes that can be requested from the user
+const (
+	ScopeIdentify    = "identify"
+	ScopeEmail       = "email"
+	ScopeConnections = "connections"
+	ScopeGuilds      = "guilds"
+	ScopeGuildsJoin  = "guilds.join"
+	ScopeBot         = "bot"
+)
+
+// Config describes the application registered on Discord
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+
+	// Endpoints, AuthURL and TokenURL are used when left empty
+	AuthURL  string
+	TokenURL string
+
+	// Used to reach the token endpoint, http.DefaultClient when nil
+	HTTPClient *http.Client
+}
+
+// Token is what the token endpoint grants
+type Token struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	Scope        string `json:"scope"`
+
+	// Computed from ExpiresIn when the token is received
+	Expiry time.Time `json:"-"`
+}
+
+// Expired tells whether the access token should be refreshed
+func (t *Token) Expired() bool {
+	return !t.Expiry.IsZero() && time.Now().After(t.Expiry)
+}
+
+// Client returns a discord.Client authenticated with the access token
+func (t *Token) Client() (*discord.Client, error) {
+	client := &discord.Client{}
+	tokenType := t.TokenType
+	if tokenType == "" {
+		tokenType = "Bearer"
+	}
+	if err := client.LoginWithToken(tokenType + " " + t.AccessToken); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// Error is returned when the token endpoint refuses a grant
+type Error struct {
+	StatusCode  int
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *Error) Error() string {
+	if e.Description == "" {
+		return fmt.Sprintf("oauth2: HTTP %d: %s", e.StatusCode, e.Code)
+	}
+	return fmt.Sprintf("oauth2: HTTP %d: %s: %s", e.StatusCode, e.Code, e.Description)
+}
+
+// AuthCodeURL returns the URL to send the user to in order to grant access,
+// state is given back on the redirect and should be checked by the caller
+func (c *Config) AuthCodeURL(state string) string {
+	v := url.Values{
+		"response_type": {"code"},
+		"client_id":     {c.ClientID},
+	}
+	if c.RedirectURL != "" {
+		v.Set("redirect_uri", c.RedirectURL)
+	}
+	if len(c.Scopes) > 0 {
+		v.Set("scope", strings.Join(c.Scopes, " "))
+	}
+	if state != "" {
+		v.Set("state", state)
+	}
+
+	authURL := c.AuthURL
+	if authURL == "" {
+		authURL = AuthURL
+	}
+	return authURL + "?" + v.Encode()
+}
+
+// Exchange trades the code received on the redirect URL for a token
+func (c *Config) Exchange(code string) (*Token, error) {
+	return c.retrieveToken(url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {c.RedirectURL},
+	})
+}
+
+// Refresh gets a new token from the refresh token of an expired one
+func (c *Config) Refresh(refreshToken string) (*Token, error) {
+	return c.retrieveToken(url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+		"redirect_uri":  {c.RedirectURL},
+	})
+}
+
+// ClientCredentials gets a token for the application owner's account
+func (c *Config) ClientCredentials() (*Token, error) {
+	v := url.Values{
+		"grant_type": {"client_credentials"},
+	}
+	if len(c.Scopes) > 0 {
+		v.Set("scope", strings.Join(c.Scopes, " "))
+	}
+	return c.retrieveToken(v)
+}
+
+func (c *Config) retrieveToken(v url.Values) (*Token, error) {
+	v.Set("client_id", c.ClientID)
+	v.Set("client_secret", c.ClientSecret)
+
+	tokenURL := c.TokenURL
+	if tokenURL == "" {
+		tokenURL = TokenURL
+	}
+	req, err := http.NewRequest("POST", tokenURL, strings.NewReader(v.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 400 {
+		oauthErr := &Error{StatusCode: resp.StatusCode}
+		// Body is not always JSON, the status is enough in that case
+		json.Unmarshal(body, oauthErr)
+		return nil, oauthErr
+	}
+
+	var token Token
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, err
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("oauth2: no access token in response")
+	}
+	if token.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+
+	return &token, nil
+}
@@ -0,0 +1,141 @@
+package oauth2
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// tokenServer stands in for the Discord token endpoint, it records the last
+// form it received and grants a token unless the code is "bad"
+func tokenServer(t *testing.T, form *url.Values) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		*form = r.PostForm
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.PostForm.Get("code") == "bad" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{
+				"error":             "invalid_grant",
+				"error_description": "Invalid \"code\" in request.",
+			})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  "access-" + r.PostForm.Get("grant_type"),
+			"token_type":    "Bearer",
+			"refresh_token": "refresh",
+			"expires_in":    604800,
+			"scope":         "identify",
+		})
+	}))
+}
+
+func testConfig(server *httptest.Server) *Config {
+	return &Config{
+		ClientID:     "id",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost/callback",
+		Scopes:       []string{ScopeIdentify},
+		TokenURL:     server.URL,
+		HTTPClient:   server.Client(),
+	}
+}
+
+func TestGrants(t *testing.T) {
+	var form url.Values
+	server := tokenServer(t, &form)
+	defer server.Close()
+	config := testConfig(server)
+
+	tests := []struct {
+		name  string
+		grant func() (*Token, error)
+		want  map[string]string
+	}{
+		{
+			"Exchange",
+			func() (*Token, error) { return config.Exchange("good") },
+			map[string]string{
+				"grant_type":   "authorization_code",
+				"code":         "good",
+				"redirect_uri": "http://localhost/callback",
+			},
+		},
+		{
+			"Refresh",
+			func() (*Token, error) { return config.Refresh("refresh") },
+			map[string]string{
+				"grant_type":    "refresh_token",
+				"refresh_token": "refresh",
+			},
+		},
+		{
+			"ClientCredentials",
+			func() (*Token, error) { return config.ClientCredentials() },
+			map[string]string{
+				"grant_type": "client_credentials",
+				"scope":      "identify",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		token, err := test.grant()
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+
+		for key, value := range test.want {
+			if got := form.Get(key); got != value {
+				t.Errorf("%s: form %s = %q, want %q", test.name, key, got, value)
+			}
+		}
+		if form.Get("client_id") != "id" || form.Get("client_secret") != "secret" {
+			t.Errorf("%s: client credentials not sent: %v", test.name, form)
+		}
+
+		if want := "access-" + test.want["grant_type"]; token.AccessToken != want {
+			t.Errorf("%s: AccessToken = %q, want %q", test.name, token.AccessToken, want)
+		}
+		if token.RefreshToken != "refresh" {
+			t.Errorf("%s: RefreshToken = %q, want %q", test.name, token.RefreshToken, "refresh")
+		}
+		if token.Expiry.IsZero() || token.Expired() {
+			t.Errorf("%s: Expiry = %v, want a week from now", test.name, token.Expiry)
+		}
+	}
+}
+
+func TestError(t *testing.T) {
+	var form url.Values
+	server := tokenServer(t, &form)
+	defer server.Close()
+
+	token, err := testConfig(server).Exchange("bad")
+	if token != nil {
+		t.Errorf("token = %v, want nil", token)
+	}
+	oauthErr, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("error = %#v, want *Error", err)
+	}
+	if oauthErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("StatusCode = %d, want %d", oauthErr.StatusCode, http.StatusBadRequest)
+	}
+	if oauthErr.Code != "invalid_grant" {
+		t.Errorf("Code = %q, want %q", oauthErr.Code, "invalid_grant")
+	}
+	if oauthErr.Description != "Invalid \"code\" in request." {
+		t.Errorf("Description = %q", oauthErr.Description)
+	}
+}
@@ -0,0 +1,278 @@
+package discord
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Permissions is the bitfield of what a role or member is allowed to do
+type Permissions int64
+
+// Permission bits, see https://discordapp.com/developers/docs/topics/permissions
+const (
+	PermissionCreateInstantInvite Permissions = 1 << iota
+	PermissionKickMembers
+	PermissionBanMembers
+	PermissionAdministrator
+	PermissionManageChannels
+	PermissionManageServer
+	PermissionAddReactions
+	PermissionViewAuditLog
+	_
+	_
+	PermissionReadMessages
+	PermissionSendMessages
+	PermissionSendTTSMessages
+	PermissionManageMessages
+	PermissionEmbedLinks
+	PermissionAttachFiles
+	PermissionReadMessageHistory
+	PermissionMentionEveryone
+	PermissionUseExternalEmojis
+	_
+	PermissionVoiceConnect
+	PermissionVoiceSpeak
+	PermissionVoiceMuteMembers
+	PermissionVoiceDeafenMembers
+	PermissionVoiceMoveMembers
+	PermissionVoiceUseVAD
+	PermissionChangeNickname
+	PermissionManageNicknames
+	PermissionManageRoles
+	PermissionManageWebhooks
+	PermissionManageEmojis
+
+	PermissionAll = PermissionCreateInstantInvite |
+		PermissionKickMembers |
+		PermissionBanMembers |
+		PermissionAdministrator |
+		PermissionManageChannels |
+		PermissionManageServer |
+		PermissionAddReactions |
+		PermissionViewAuditLog |
+		PermissionReadMessages |
+		PermissionSendMessages |
+		PermissionSendTTSMessages |
+		PermissionManageMessages |
+		PermissionEmbedLinks |
+		PermissionAttachFiles |
+		PermissionReadMessageHistory |
+		PermissionMentionEveryone |
+		PermissionUseExternalEmojis |
+		PermissionVoiceConnect |
+		PermissionVoiceSpeak |
+		PermissionVoiceMuteMembers |
+		PermissionVoiceDeafenMembers |
+		PermissionVoiceMoveMembers |
+		PermissionVoiceUseVAD |
+		PermissionChangeNickname |
+		PermissionManageNicknames |
+		PermissionManageRoles |
+		PermissionManageWebhooks |
+		PermissionManageEmojis
+)
+
+var permissionNames = []struct {
+	permission Permissions
+	name       string
+}{
+	{PermissionCreateInstantInvite, "CREATE_INSTANT_INVITE"},
+	{PermissionKickMembers, "KICK_MEMBERS"},
+	{PermissionBanMembers, "BAN_MEMBERS"},
+	{PermissionAdministrator, "ADMINISTRATOR"},
+	{PermissionManageChannels, "MANAGE_CHANNELS"},
+	{PermissionManageServer, "MANAGE_GUILD"},
+	{PermissionAddReactions, "ADD_REACTIONS"},
+	{PermissionViewAuditLog, "VIEW_AUDIT_LOG"},
+	{PermissionReadMessages, "READ_MESSAGES"},
+	{PermissionSendMessages, "SEND_MESSAGES"},
+	{PermissionSendTTSMessages, "SEND_TTS_MESSAGES"},
+	{PermissionManageMessages, "MANAGE_MESSAGES"},
+	{PermissionEmbedLinks, "EMBED_LINKS"},
+	{PermissionAttachFiles, "ATTACH_FILES"},
+	{PermissionReadMessageHistory, "READ_MESSAGE_HISTORY"},
+	{PermissionMentionEveryone, "MENTION_EVERYONE"},
+	{PermissionUseExternalEmojis, "USE_EXTERNAL_EMOJIS"},
+	{PermissionVoiceConnect, "CONNECT"},
+	{PermissionVoiceSpeak, "SPEAK"},
+	{PermissionVoiceMuteMembers, "MUTE_MEMBERS"},
+	{PermissionVoiceDeafenMembers, "DEAFEN_MEMBERS"},
+	{PermissionVoiceMoveMembers, "MOVE_MEMBERS"},
+	{PermissionVoiceUseVAD, "USE_VAD"},
+	{PermissionChangeNickname, "CHANGE_NICKNAME"},
+	{PermissionManageNicknames, "MANAGE_NICKNAMES"},
+	{PermissionManageRoles, "MANAGE_ROLES"},
+	{PermissionManageWebhooks, "MANAGE_WEBHOOKS"},
+	{PermissionManageEmojis, "MANAGE_EMOJIS"},
+}
+
+// Has tells whether every bit of p is set
+func (perms Permissions) Has(p Permissions) bool {
+	return perms&p == p
+}
+
+// String returns the names of the set bits, such as "KICK_MEMBERS|BAN_MEMBERS"
+func (perms Permissions) String() string {
+	var names []string
+	for _, pn := range permissionNames {
+		if perms&pn.permission != 0 {
+			names = append(names, pn.name)
+		}
+	}
+	if len(names) == 0 {
+		return "0"
+	}
+	return strings.Join(names, "|")
+}
+
+// Types of PermissionOverwrite
+const (
+	OverwriteRole   = "role"
+	OverwriteMember = "member"
+)
+
+// PermissionOverwrite changes the permissions of a role or a member in one
+// channel
+type PermissionOverwrite struct {
+	ID    string      `json:"id"`
+	Type  string      `json:"type"`
+	Allow Permissions `json:"allow"`
+	Deny  Permissions `json:"deny"`
+}
+
+// UserChannelPermissions computes the permissions of the user in the channel
+// from the cached roles and overwrites
+func (c *Client) UserChannelPermissions(userID string, channelID string) (Permissions, error) {
+	channel := c.GetChannelByID(channelID)
+	if channel.ID == "" {
+		return 0, fmt.Errorf("channel %s not found", channelID)
+	}
+
+	c.serversLock.RLock()
+	defer c.serversLock.RUnlock()
+	server, ok := c.Servers[channel.ServerID]
+	if !ok {
+		return 0, fmt.Errorf("server %s not found", channel.ServerID)
+	}
+
+	member := server.getMember(userID)
+	if member == nil {
+		return 0, fmt.Errorf("member %s not found in server %s", userID, server.ID)
+	}
+
+	return memberPermissions(&server, &channel, member), nil
+}
+
+// memberServerPermissions applies, in order: the owner's rights, @everyone,
+// the member's roles and the administrator bit
+func memberServerPermissions(server *Server, member *Member) Permissions {
+	if server.OwnerID == member.User.ID {
+		return PermissionAll
+	}
+
+	// @everyone shares the ID of the server
+	perms := server.GetRole(server.ID).Permissions
+	for _, roleID := range member.Roles {
+		perms |= server.GetRole(roleID).Permissions
+	}
+
+	if perms.Has(PermissionAdministrator) {
+		return PermissionAll
+	}
+	return perms
+}
+
+// memberPermissions applies the channel overwrites for @everyone, the
+// member's roles and the member itself on top of the server permissions
+func memberPermissions(server *Server, channel *Channel, member *Member) Permissions {
+	perms := memberServerPermissions(server, member)
+	if perms == PermissionAll {
+		return perms
+	}
+
+	for _, overwrite := range channel.PermissionOverwrites {
+		if overwrite.ID == server.ID {
+			perms &^= overwrite.Deny
+			perms |= overwrite.Allow
+			break
+		}
+	}
+
+	var allow, deny Permissions
+	for _, overwrite := range channel.PermissionOverwrites {
+		if overwrite.Type != OverwriteRole {
+			continue
+		}
+		for _, roleID := range member.Roles {
+			if overwrite.ID == roleID {
+				allow |= overwrite.Allow
+				deny |= overwrite.Deny
+				break
+			}
+		}
+	}
+	perms &^= deny
+	perms |= allow
+
+	for _, overwrite := range channel.PermissionOverwrites {
+		if overwrite.Type == OverwriteMember && overwrite.ID == member.User.ID {
+			perms &^= overwrite.Deny
+			perms |= overwrite.Allow
+			break
+		}
+	}
+
+	return perms
+}
+
+// MissingPermissionsError is returned instead of sending a request which
+// would be refused, when Client.CheckPermissions is set
+type MissingPermissionsError struct {
+	Missing Permissions
+}
+
+func (e *MissingPermissionsError) Error() string {
+	return fmt.Sprintf("missing permissions: %s", e.Missing)
+}
+
+// checkServerPermissions makes sure the client has the required permissions
+// in the server. Unknown servers or members are not checked, Discord will
+// answer for them.
+func (c *Client) checkServerPermissions(serverID string, required Permissions) error {
+	if !c.CheckPermissions {
+		return nil
+	}
+
+	c.serversLock.RLock()
+	defer c.serversLock.RUnlock()
+	server, ok := c.Servers[serverID]
+	if !ok {
+		return nil
+	}
+	member := server.getMember(c.User.ID)
+	if member == nil {
+		return nil
+	}
+
+	if missing := required &^ memberServerPermissions(&server, member); missing != 0 {
+		return &MissingPermissionsError{Missing: missing}
+	}
+	return nil
+}
+
+// checkChannelPermissions makes sure the client has the required permissions
+// in the channel, private or unknown channels are not checked
+func (c *Client) checkChannelPermissions(channelID string, required Permissions) error {
+	if !c.CheckPermissions {
+		return nil
+	}
+	perms, err := c.UserChannelPermissions(c.User.ID, channelID)
+	if err != nil {
+		return nil
+	}
+
+	if missing := required &^ perms; missing != 0 {
+		return &MissingPermissionsError{Missing: missing}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package discord
+
+import "testing"
+
+func TestMemberPermissions(t *testing.T) {
+	const (
+		serverID = "1"
+		ownerID  = "2"
+		userID   = "3"
+		adminID  = "10"
+		modID    = "11"
+	)
+	everyone := PermissionReadMessages | PermissionSendMessages
+
+	server := Server{
+		ID:      serverID,
+		OwnerID: ownerID,
+		Roles: []Role{
+			{ID: serverID, Name: "@everyone", Permissions: everyone},
+			{ID: adminID, Name: "admin", Permissions: PermissionAdministrator},
+			{ID: modID, Name: "mod", Permissions: PermissionManageMessages},
+		},
+	}
+
+	everyoneDeny := PermissionOverwrite{ID: serverID, Type: OverwriteRole, Deny: PermissionSendMessages}
+	modAllow := PermissionOverwrite{ID: modID, Type: OverwriteRole, Allow: PermissionSendMessages}
+	memberDeny := PermissionOverwrite{ID: userID, Type: OverwriteMember, Deny: PermissionSendMessages}
+
+	tests := []struct {
+		name       string
+		member     Member
+		overwrites []PermissionOverwrite
+		want       Permissions
+	}{
+		{
+			"owner",
+			Member{User: User{ID: ownerID}},
+			[]PermissionOverwrite{everyoneDeny},
+			PermissionAll,
+		},
+		{
+			"administrator",
+			Member{User: User{ID: userID}, Roles: []string{adminID}},
+			[]PermissionOverwrite{everyoneDeny, memberDeny},
+			PermissionAll,
+		},
+		{
+			"no overwrites",
+			Member{User: User{ID: userID}, Roles: []string{modID}},
+			nil,
+			everyone | PermissionManageMessages,
+		},
+		{
+			"@everyone deny",
+			Member{User: User{ID: userID}},
+			[]PermissionOverwrite{everyoneDeny},
+			PermissionReadMessages,
+		},
+		{
+			"role allow overrides @everyone deny",
+			Member{User: User{ID: userID}, Roles: []string{modID}},
+			[]PermissionOverwrite{everyoneDeny, modAllow},
+			everyone | PermissionManageMessages,
+		},
+		{
+			"role allow of another role",
+			Member{User: User{ID: userID}},
+			[]PermissionOverwrite{everyoneDeny, modAllow},
+			PermissionReadMessages,
+		},
+		{
+			"member deny overrides role allow",
+			Member{User: User{ID: userID}, Roles: []string{modID}},
+			[]PermissionOverwrite{memberDeny, modAllow, everyoneDeny},
+			PermissionReadMessages | PermissionManageMessages,
+		},
+	}
+
+	for _, test := range tests {
+		channel := Channel{ID: "20", ServerID: serverID, PermissionOverwrites: test.overwrites}
+		member := test.member
+		if got := memberPermissions(&server, &channel, &member); got != test.want {
+			t.Errorf("%s: got %s, want %s", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package discord
+
+// Reaction is the count of one emoji added to a message
+type Reaction struct {
+	Count int   `json:"count"`
+	Me    bool  `json:"me"`
+	Emoji Emoji `json:"emoji"`
+}
+
+// MessageReaction is received when a reaction is added to or removed from
+// a message, UserID and Emoji are empty when all reactions are removed
+type MessageReaction struct {
+	UserID    string `json:"user_id"`
+	ChannelID string `json:"channel_id"`
+	MessageID string `json:"message_id"`
+	Emoji     Emoji  `json:"emoji"`
+}
+
+type messageReactionEvent struct {
+	OpCode int             `json:"op"`
+	Type   string          `json:"t"`
+	Data   MessageReaction `json:"d"`
+}
@@ -2,11 +2,23 @@ package discord
 
 // Role defines a role on a Discord server
 type Role struct {
-	Name        string `json:"name"`
-	ID          string `json:"id"`
-	Managed     bool   `json:"managed"`
-	Position    int    `json:"position"`
-	Permissions int    `json:"permissions"`
-	Hoist       bool   `json:"hoist"`
-	Color       int    `json:"color"`
+	Name        string      `json:"name"`
+	ID          string      `json:"id"`
+	Managed     bool        `json:"managed"`
+	Position    int         `json:"position"`
+	Permissions Permissions `json:"permissions"`
+	Hoist       bool        `json:"hoist"`
+	Color       int         `json:"color"`
+	Mentionable bool        `json:"mentionable"`
+}
+
+// removeString returns the slice without any occurrence of value
+func removeString(slice []string, value string) []string {
+	res := slice[:0]
+	for _, s := range slice {
+		if s != value {
+			res = append(res, s)
+		}
+	}
+	return res
 }
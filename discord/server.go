@@ -1,22 +1,108 @@
 package discord
 
+import (
+	"fmt"
+)
+
 // Server defines everything server-related, including a list of members,
 // a list of channels, the presence of each member...
 type Server struct {
-	Name         string     `json:"name"`
-	ID           string     `json:"id"`
-	OwnerID      string     `json:"owner_id"`
-	Region       string     `json:"region"`
-	AfkTimeout   int        `json:"afk_timeout"`
-	AfkChannelID string     `json:"afk_channel_id"`
-	Type         string     `json:"type"`
-	Icon         string     `json:"icon"`
-	JoinedAt     string     `json:"joined_at"`
-	Large        bool       `json:"large"`
-	Presences    []Presence `json:"presences"`
-	Roles        []Role     `json:"roles"`
-	Members      []Member   `json:"members`
-	Channels     []Channel  `json:"channels"`
+	Name              string     `json:"name"`
+	ID                string     `json:"id"`
+	OwnerID           string     `json:"owner_id"`
+	Region            string     `json:"region"`
+	AfkTimeout        int        `json:"afk_timeout"`
+	AfkChannelID      string     `json:"afk_channel_id"`
+	Type              string     `json:"type"`
+	Icon              string     `json:"icon"`
+	Splash            string     `json:"splash"`
+	VerificationLevel int        `json:"verification_level"`
+	JoinedAt          string     `json:"joined_at"`
+	Large             bool       `json:"large"`
+	Presences         []Presence `json:"presences"`
+	Roles             []Role     `json:"roles"`
+	Emojis            []Emoji    `json:"emojis"`
+	Members           []Member   `json:"members"`
+	Channels          []Channel  `json:"channels"`
+}
+
+// GetEmoji returns the custom emoji of the server with the given name
+func (server *Server) GetEmoji(name string) Emoji {
+	var res Emoji
+	for _, emoji := range server.Emojis {
+		if emoji.Name == name {
+			res = emoji
+			break
+		}
+	}
+	return res
+}
+
+// Verification levels a member must meet before sending messages
+const (
+	VerificationNone   = 0
+	VerificationLow    = 1 // Verified email
+	VerificationMedium = 2 // Registered for 5 minutes
+	VerificationHigh   = 3 // Member of the server for 10 minutes
+)
+
+// ServerParams holds the changes to apply to a server, empty fields are
+// left untouched
+type ServerParams struct {
+	Name         string
+	Region       string
+	AfkChannelID string
+	// In seconds
+	AfkTimeout int
+	// Raw image (PNG, JPEG or GIF)
+	Icon []byte
+	// Transfers the ownership, only the owner can do it
+	OwnerID           string
+	VerificationLevel *int
+}
+
+// IconURL returns the server's icon URL
+func (server *Server) IconURL() string {
+	if server.Icon != "" {
+		return fmt.Sprintf("%s/%s/icons/%s.jpg", apiServers, server.ID, server.Icon)
+	}
+	return ""
+}
+
+// GetRole returns the role of the server with the given ID
+func (server *Server) GetRole(roleID string) Role {
+	var res Role
+	for _, role := range server.Roles {
+		if role.ID == roleID {
+			res = role
+			break
+		}
+	}
+	return res
+}
+
+// getMember returns the member of the server with the given user ID, or nil
+func (server *Server) getMember(userID string) *Member {
+	for i := range server.Members {
+		if server.Members[i].User.ID == userID {
+			return &server.Members[i]
+		}
+	}
+	return nil
+}
+
+// PruneCount returns the number of members who would be kicked by Prune
+func (server *Server) PruneCount(client *Client, days int) (int, error) {
+	return client.PruneCount(server.ID, days)
+}
+
+// Prune kicks the members inactive for the given number of days
+func (server *Server) Prune(client *Client, days int, opts ...RequestOption) (int, error) {
+	return client.Prune(server.ID, days, opts...)
+}
+
+type pruneResult struct {
+	Pruned int `json:"pruned"`
 }
 
 type serverEvent struct {
@@ -25,9 +111,31 @@ type serverEvent struct {
 	Data   Server `json:"d"`
 }
 
+// Ban is a user banned from a server
+type Ban struct {
+	User     User   `json:"user"`
+	Reason   string `json:"reason"`
+	ServerID string `json:"guild_id"`
+}
+
+// BanParams holds the options of a ban
+type BanParams struct {
+	// Delete the messages sent by the user in the last days, up to 7
+	DeleteMessageDays int
+	// Recorded in the audit log
+	Reason string
+}
+
+type banEvent struct {
+	OpCode int    `json:"op"`
+	Type   string `json:"t"`
+	Data   Ban    `json:"d"`
+}
+
 // Member defines a server member from the Ready event
 type Member struct {
 	User     User     `json:"user"`
+	Nick     string   `json:"nick"`
 	Roles    []string `json:"roles"`
 	Muted    bool     `json:"mute"`
 	Deafed   bool     `json:"deaf"`
@@ -35,6 +143,27 @@ type Member struct {
 	ServerID string   `json:"guild_id"`
 }
 
+// DisplayName returns the nickname of the member, or its username if none
+func (member *Member) DisplayName() string {
+	if member.Nick != "" {
+		return member.Nick
+	}
+	return member.User.Name
+}
+
+// MemberParams holds the changes to apply to a member, nil fields are left
+// untouched
+type MemberParams struct {
+	// Empty string removes the nickname
+	Nick *string `json:"nick,omitempty"`
+	// Replaces every role of the member, an empty slice removes them all
+	Roles *[]string `json:"roles,omitempty"`
+	Mute  *bool     `json:"mute,omitempty"`
+	Deaf  *bool     `json:"deaf,omitempty"`
+	// Voice channel to move the member to
+	ChannelID string `json:"channel_id,omitempty"`
+}
+
 type memberEvent struct {
 	OpCode int    `json:"op"`
 	Type   string `json:"t"`
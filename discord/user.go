@@ -1,7 +1,9 @@
 package discord
 
 import (
+	"encoding/base64"
 	"fmt"
+	"net/http"
 )
 
 // User defines a user of Disord
@@ -16,6 +18,27 @@ type User struct {
 	// Discriminator string `json:"discriminator,string"`
 }
 
+// ProfileParams holds the changes to apply to the client's own account,
+// empty fields are left untouched
+type ProfileParams struct {
+	Username string
+	Email    string
+	// Current password, required by Discord for any change
+	Password    string
+	NewPassword string
+	// Raw image (PNG, JPEG or GIF) to use as avatar
+	Avatar []byte
+}
+
+// imageDataURI encodes an image the way Discord expects it in JSON payloads
+func imageDataURI(image []byte) string {
+	return fmt.Sprintf(
+		"data:%s;base64,%s",
+		http.DetectContentType(image),
+		base64.StdEncoding.EncodeToString(image),
+	)
+}
+
 // GetAvatarURL returns the user's avatar URL
 func (u *User) AvatarURL() string {
 	if u.Avatar != "" {
@@ -25,18 +48,18 @@ func (u *User) AvatarURL() string {
 }
 
 // Ban bans the user from the given server
-func (u *User) Ban(client *Client, server Server) error {
-	return client.Ban(server, *u)
+func (u *User) Ban(client *Client, server Server, opts ...RequestOption) error {
+	return client.Ban(server, *u, opts...)
 }
 
 // Unban unbans the user from the given server
-func (u *User) Unban(client *Client, server Server) error {
-	return client.Unban(server, *u)
+func (u *User) Unban(client *Client, server Server, opts ...RequestOption) error {
+	return client.Unban(server, *u, opts...)
 }
 
 // Kick kicks the user from the given server
-func (u *User) Kick(client *Client, server Server) error {
-	return client.Kick(server, *u)
+func (u *User) Kick(client *Client, server Server, opts ...RequestOption) error {
+	return client.Kick(server, *u, opts...)
 }
 
 // CreatePrivateChannel creates a private channel with this user
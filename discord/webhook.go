@@ -0,0 +1,219 @@
+package discord
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Webhook posts messages to a channel without a connected user
+type Webhook struct {
+	ID        string `json:"id"`
+	ServerID  string `json:"guild_id"`
+	ChannelID string `json:"channel_id"`
+	// Creator of the webhook, not set when fetched with its token
+	User   User   `json:"user"`
+	Name   string `json:"name"`
+	Avatar string `json:"avatar"`
+	Token  string `json:"token"`
+}
+
+// WebhookParams holds the changes to apply to a webhook, empty fields are
+// left untouched
+type WebhookParams struct {
+	Name string
+	// Raw image (PNG, JPEG or GIF)
+	Avatar []byte
+	// Moves the webhook to another channel of the server
+	ChannelID string
+}
+
+// WebhookMessageParams holds everything that can be posted by a webhook
+type WebhookMessageParams struct {
+	Content string `json:"content,omitempty"`
+	// Override the name and avatar of the webhook for this message
+	Username  string  `json:"username,omitempty"`
+	AvatarURL string  `json:"avatar_url,omitempty"`
+	TTS       bool    `json:"tts,omitempty"`
+	Embeds    []Embed `json:"embeds,omitempty"`
+	Files     []File  `json:"-"`
+	// Wait for the message to be posted, and return it
+	Wait bool `json:"-"`
+}
+
+// ParseWebhookURL returns the ID and token of a webhook from its URL, as
+// displayed in the Discord settings
+func ParseWebhookURL(webhookURL string) (string, string, error) {
+	i := strings.Index(webhookURL, "/webhooks/")
+	if i < 0 {
+		return "", "", fmt.Errorf("not a webhook URL: %s", webhookURL)
+	}
+	parts := strings.Split(strings.Trim(webhookURL[i+len("/webhooks/"):], "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("not a webhook URL: %s", webhookURL)
+	}
+	return parts[0], parts[1], nil
+}
+
+// CreateWebhook creates a webhook in the channel, avatar may be nil
+func (c *Client) CreateWebhook(channelID string, name string, avatar []byte, opts ...RequestOption) (Webhook, error) {
+	var webhook Webhook
+
+	if err := c.checkChannelPermissions(channelID, PermissionManageWebhooks); err != nil {
+		return webhook, err
+	}
+
+	m := map[string]interface{}{
+		"name": name,
+	}
+	if avatar != nil {
+		m["avatar"] = imageDataURI(avatar)
+	}
+
+	response, err := c.request(
+		"POST",
+		fmt.Sprintf("%s/%s/webhooks", apiChannels, channelID),
+		m,
+		opts...,
+	)
+	if err != nil {
+		return webhook, err
+	}
+
+	if err := json.Unmarshal(response, &webhook); err != nil {
+		return webhook, err
+	}
+
+	return webhook, nil
+}
+
+// ChannelWebhooks returns the webhooks of the channel
+func (c *Client) ChannelWebhooks(channelID string) ([]Webhook, error) {
+	var webhooks []Webhook
+
+	if err := c.checkChannelPermissions(channelID, PermissionManageWebhooks); err != nil {
+		return webhooks, err
+	}
+
+	response, err := c.get(fmt.Sprintf("%s/%s/webhooks", apiChannels, channelID))
+	if err != nil {
+		return webhooks, err
+	}
+
+	if err := json.Unmarshal(response, &webhooks); err != nil {
+		return webhooks, err
+	}
+
+	return webhooks, nil
+}
+
+// ServerWebhooks returns the webhooks of every channel of the server
+func (c *Client) ServerWebhooks(serverID string) ([]Webhook, error) {
+	var webhooks []Webhook
+
+	if err := c.checkServerPermissions(serverID, PermissionManageWebhooks); err != nil {
+		return webhooks, err
+	}
+
+	response, err := c.get(fmt.Sprintf("%s/%s/webhooks", apiServers, serverID))
+	if err != nil {
+		return webhooks, err
+	}
+
+	if err := json.Unmarshal(response, &webhooks); err != nil {
+		return webhooks, err
+	}
+
+	return webhooks, nil
+}
+
+// EditWebhook changes the name, avatar or channel of the webhook
+func (c *Client) EditWebhook(webhookID string, params WebhookParams, opts ...RequestOption) (Webhook, error) {
+	var webhook Webhook
+
+	m := make(map[string]interface{})
+	if params.Name != "" {
+		m["name"] = params.Name
+	}
+	if params.Avatar != nil {
+		m["avatar"] = imageDataURI(params.Avatar)
+	}
+	if params.ChannelID != "" {
+		m["channel_id"] = params.ChannelID
+	}
+
+	response, err := c.request(
+		"PATCH",
+		fmt.Sprintf("%s/%s", apiWebhooks, webhookID),
+		m,
+		opts...,
+	)
+	if err != nil {
+		return webhook, err
+	}
+
+	if err := json.Unmarshal(response, &webhook); err != nil {
+		return webhook, err
+	}
+
+	return webhook, nil
+}
+
+// DeleteWebhook deletes the webhook
+func (c *Client) DeleteWebhook(webhookID string, opts ...RequestOption) error {
+	_, err := c.request(
+		"DELETE",
+		fmt.Sprintf("%s/%s", apiWebhooks, webhookID),
+		nil,
+		opts...,
+	)
+	return err
+}
+
+// ExecuteWebhook posts a message through the webhook. Only its token is
+// needed, the client does not have to be logged in (see the package-level
+// ExecuteWebhook function to post without a Client). The message is only
+// returned when params.Wait is set.
+func (c *Client) ExecuteWebhook(webhookID string, token string, params WebhookMessageParams) (Message, error) {
+	var message Message
+	var response []byte
+	var err error
+
+	for i := range params.Embeds {
+		if err := params.Embeds[i].Validate(); err != nil {
+			return message, err
+		}
+	}
+
+	u := fmt.Sprintf("%s/%s/%s", apiWebhooks, webhookID, token)
+	if params.Wait {
+		u += "?wait=true"
+	}
+
+	if len(params.Files) > 0 {
+		response, err = c.requestMultipart("POST", u, params, params.Files)
+	} else {
+		response, err = c.request("POST", u, params)
+	}
+	if err != nil {
+		return message, err
+	}
+
+	// Discord answers with no content unless asked to wait
+	if !params.Wait {
+		return message, nil
+	}
+
+	if err := json.Unmarshal(response, &message); err != nil {
+		return message, err
+	}
+
+	return message, nil
+}
+
+// ExecuteWebhook posts a message through the webhook without a Client,
+// e.g. for notifications from scripts
+func ExecuteWebhook(webhookID string, token string, params WebhookMessageParams) (Message, error) {
+	var c Client
+	return c.ExecuteWebhook(webhookID, token, params)
+}